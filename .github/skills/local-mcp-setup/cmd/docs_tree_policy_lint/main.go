@@ -0,0 +1,588 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// treeRule applies to every markdown file whose repo-relative path matches
+// Glob. Empty checks are skipped.
+type treeRule struct {
+	ID                     string   `json:"id"`
+	Glob                   string   `json:"glob"`
+	AllowedDocTypes        []string `json:"allowed_doc_types"`
+	RequiredFrontMatter    []string `json:"required_front_matter"`
+	ForbiddenTitlePatterns []string `json:"forbidden_title_patterns"`
+	NamingPattern          string   `json:"naming_pattern"`
+	Exempt                 []string `json:"exempt"`
+	globRegex              *regexp.Regexp
+	titleRegexes           []*regexp.Regexp
+	namingRegex            *regexp.Regexp
+}
+
+type treePolicy struct {
+	Source string     `json:"source"`
+	Rules  []treeRule `json:"rules"`
+}
+
+type treeViolation struct {
+	Rule    string `json:"rule"`
+	Path    string `json:"path"`
+	Check   string `json:"check"`
+	Message string `json:"message"`
+}
+
+type ruleSummary struct {
+	ID         string `json:"id"`
+	Glob       string `json:"glob"`
+	Files      int    `json:"files"`
+	Violations int    `json:"violations"`
+}
+
+const defaultPlanStoryPattern = "PLAN-{id}-{slug}.md"
+
+// defaultPolicy keeps docs/feedback to feedback notes and requires story plan
+// files to follow the planning profile's naming pattern. The planning control
+// documents other commands write or require under docs/plans are exempt.
+func defaultPolicy() treePolicy {
+	return treePolicy{
+		Source: "built-in",
+		Rules: []treeRule{
+			{
+				ID:              "feedback-tree",
+				Glob:            "docs/feedback/**",
+				AllowedDocTypes: []string{"feedback", "issue", "finding", "report", "review"},
+				ForbiddenTitlePatterns: []string{
+					`(?i)^#\s*(implementation plan|technology constraints|traceability matrix|repo topology decision|openapi contract plan|product intent|requirements|backlog|phase gate|severity classification|architecture decision record)\b`,
+				},
+				Exempt: []string{"README.md"},
+			},
+			{
+				ID:            "plan-story-files",
+				Glob:          "docs/plans/*.md",
+				NamingPattern: "{plan_story_file_pattern}",
+				Exempt: []string{
+					"README.md", "index.md", "planning-signoff.md", "control-applicability-matrix.md",
+					"repo-change-plan.md", "model-boundary-classification.md", "intent-control-accountability.md",
+				},
+			},
+		},
+	}
+}
+
+func main() {
+	targetRoot := flag.String("target-root", "", "target repository root (defaults to cwd)")
+	policyFile := flag.String("policy-file", "docs/tooling/docs-tree-policy.yaml", "docs tree policy YAML; a missing default file uses the built-in policy")
+	profileFile := flag.String("profile-file", "", "planning behavior profile providing plan_story_file_pattern (defaults to standard profile locations)")
+	openapiLint := flag.String("openapi-lint", "", "openapi_lint command source used to decode the policy YAML (defaults to .github/skills/local-mcp-setup/cmd/openapi_lint/main.go under the working directory, then the target root)")
+	docsDir := flag.String("docs-dir", "docs", "docs tree to evaluate, relative to the target root")
+	flag.Parse()
+
+	root := strings.TrimSpace(*targetRoot)
+	if root == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			printBlocked([]string{"unable to determine working directory"}, nil)
+			return
+		}
+		root = cwd
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		printBlocked([]string{"invalid target root"}, nil)
+		return
+	}
+
+	policy, err := loadPolicy(absRoot, strings.TrimSpace(*policyFile), flagSet("policy-file"), openapiLintCommand(absRoot, strings.TrimSpace(*openapiLint)))
+	if err != nil {
+		printBlocked([]string{fmt.Sprintf("invalid docs tree policy: %v", err)}, nil)
+		return
+	}
+	planPattern, profilePath := planStoryPattern(absRoot, strings.TrimSpace(*profileFile))
+	if err := compilePolicy(&policy, planPattern); err != nil {
+		printBlocked([]string{fmt.Sprintf("invalid docs tree policy: %v", err)}, map[string]any{"policy_source": policy.Source})
+		return
+	}
+
+	docsRoot := filepath.Join(absRoot, filepath.FromSlash(strings.TrimSpace(*docsDir)))
+	files := []string{}
+	_ = filepath.WalkDir(docsRoot, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !isMarkdown(path) {
+			return nil
+		}
+		rel, relErr := filepath.Rel(absRoot, path)
+		if relErr == nil {
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	sort.Strings(files)
+
+	violations := []treeViolation{}
+	summaries := []ruleSummary{}
+	for _, rule := range policy.Rules {
+		summary := ruleSummary{ID: rule.ID, Glob: rule.Glob}
+		for _, rel := range files {
+			if !rule.globRegex.MatchString(rel) || rule.exempt(rel) {
+				continue
+			}
+			summary.Files++
+			found := evaluateRule(rule, rel, filepath.Join(absRoot, filepath.FromSlash(rel)))
+			summary.Violations += len(found)
+			violations = append(violations, found...)
+		}
+		summaries = append(summaries, summary)
+	}
+
+	details := map[string]any{
+		"docs_root":               filepath.ToSlash(docsRoot),
+		"policy_source":           policy.Source,
+		"profile_file":            filepath.ToSlash(profilePath),
+		"plan_story_file_pattern": planPattern,
+		"rules":                   summaries,
+		"files_scanned":           len(files),
+	}
+	if len(violations) > 0 {
+		details["violations"] = violations
+		printBlocked([]string{"docs tree policy violations"}, details)
+		return
+	}
+
+	details["status"] = "PASS"
+	payload, _ := json.Marshal(details)
+	fmt.Println(string(payload))
+}
+
+func loadPolicy(root, value string, explicit bool, lintCommand string) (treePolicy, error) {
+	if value == "" {
+		return defaultPolicy(), nil
+	}
+	path := value
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, filepath.FromSlash(path))
+	}
+	if _, err := os.Stat(path); err != nil {
+		if explicit {
+			return treePolicy{}, err
+		}
+		return defaultPolicy(), nil
+	}
+	values, err := readYAMLPaths(path, lintCommand)
+	if err != nil {
+		return treePolicy{}, fmt.Errorf("%s: %v", filepath.ToSlash(path), err)
+	}
+	count := yamlItems(values, "rules")
+	if count == 0 {
+		return treePolicy{}, fmt.Errorf("%s: no rules defined", filepath.ToSlash(path))
+	}
+	policy := treePolicy{Source: filepath.ToSlash(path)}
+	for i := 0; i < count; i++ {
+		prefix := "rules." + strconv.Itoa(i)
+		if _, scalar := values[prefix]; scalar {
+			return treePolicy{}, fmt.Errorf("rule %d is not a mapping", i+1)
+		}
+		rule := treeRule{
+			ID:                     strings.TrimSpace(values[prefix+".id"]),
+			Glob:                   strings.TrimSpace(values[prefix+".glob"]),
+			AllowedDocTypes:        yamlList(values, prefix+".allowed_doc_types"),
+			RequiredFrontMatter:    yamlList(values, prefix+".required_front_matter"),
+			ForbiddenTitlePatterns: yamlList(values, prefix+".forbidden_title_patterns"),
+			NamingPattern:          strings.TrimSpace(values[prefix+".naming_pattern"]),
+			Exempt:                 yamlList(values, prefix+".exempt"),
+		}
+		if rule.ID == "" {
+			rule.ID = "rule-" + strconv.Itoa(i+1)
+		}
+		if rule.Glob == "" {
+			return treePolicy{}, fmt.Errorf("rule %s has no glob", rule.ID)
+		}
+		policy.Rules = append(policy.Rules, rule)
+	}
+	return policy, nil
+}
+
+// planStoryPattern reads plan_story_file_pattern from the planning profile so
+// naming rules follow the same convention the planner writes.
+func planStoryPattern(root, profileFile string) (string, string) {
+	candidates := []string{}
+	if profileFile != "" {
+		if !filepath.IsAbs(profileFile) {
+			profileFile = filepath.Join(root, filepath.FromSlash(profileFile))
+		}
+		candidates = append(candidates, profileFile)
+	} else {
+		candidates = append(candidates,
+			filepath.Join(root, "docs", "source", "02-architecture", "planning-behavior-profile.yaml"),
+			filepath.Join(root, "docs", "source", "DemoArchitectureDocs", "planning-behavior-profile.yaml"),
+			filepath.Join(root, ".github", "skills", "local-mcp-setup", "corporate-docs", "planning-behavior-profile.yaml"),
+		)
+	}
+	for _, candidate := range candidates {
+		values, err := readTopLevelScalars(candidate)
+		if err != nil {
+			continue
+		}
+		if pattern := strings.TrimSpace(values["plan_story_file_pattern"]); pattern != "" {
+			return pattern, candidate
+		}
+	}
+	return defaultPlanStoryPattern, ""
+}
+
+func readTopLevelScalars(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	out := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+		idx := strings.Index(trimmed, ":")
+		if idx <= 0 {
+			continue
+		}
+		key := strings.TrimSpace(trimmed[:idx])
+		value := strings.TrimSpace(trimmed[idx+1:])
+		if value != "" {
+			out[key] = strings.Trim(value, "\"'")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func exists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+func compilePolicy(policy *treePolicy, planPattern string) error {
+	for i := range policy.Rules {
+		rule := &policy.Rules[i]
+		globRegex, err := regexp.Compile(globToRegex(rule.Glob))
+		if err != nil {
+			return fmt.Errorf("rule %s glob: %v", rule.ID, err)
+		}
+		rule.globRegex = globRegex
+		for _, pattern := range rule.ForbiddenTitlePatterns {
+			titleRegex, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("rule %s forbidden title pattern: %v", rule.ID, err)
+			}
+			rule.titleRegexes = append(rule.titleRegexes, titleRegex)
+		}
+		if rule.NamingPattern != "" {
+			naming := strings.ReplaceAll(rule.NamingPattern, "{plan_story_file_pattern}", planPattern)
+			namingRegex, err := regexp.Compile(namingToRegex(naming))
+			if err != nil {
+				return fmt.Errorf("rule %s naming pattern: %v", rule.ID, err)
+			}
+			rule.NamingPattern = naming
+			rule.namingRegex = namingRegex
+		}
+	}
+	return nil
+}
+
+// globToRegex converts a slash-separated glob to an anchored regex: `**`
+// matches any number of path segments, `*` and `?` stay within one segment.
+func globToRegex(glob string) string {
+	glob = strings.TrimPrefix(filepath.ToSlash(strings.TrimSpace(glob)), "./")
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+				if i+1 < len(glob) && glob[i+1] == '/' {
+					i++
+					b.WriteString("(?:.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+				continue
+			}
+			b.WriteString("[^/]*")
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
+
+// namingToRegex turns a file naming pattern such as PLAN-{id}-{slug}.md into
+// an anchored regex; `{id}` is an alphanumeric ID and `{slug}` a kebab-case slug.
+func namingToRegex(pattern string) string {
+	placeholders := map[string]string{
+		"{id}":   `[A-Za-z0-9]+`,
+		"{slug}": `[a-z0-9]+(?:-[a-z0-9]+)*`,
+	}
+	var b strings.Builder
+	b.WriteString("^")
+	for len(pattern) > 0 {
+		matched := false
+		for token, expr := range placeholders {
+			if strings.HasPrefix(pattern, token) {
+				b.WriteString(expr)
+				pattern = pattern[len(token):]
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+		b.WriteString(regexp.QuoteMeta(pattern[:1]))
+		pattern = pattern[1:]
+	}
+	b.WriteString("$")
+	return b.String()
+}
+
+func (r treeRule) exempt(rel string) bool {
+	base := filepath.Base(rel)
+	for _, name := range r.Exempt {
+		if strings.EqualFold(base, name) || strings.EqualFold(rel, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func evaluateRule(rule treeRule, rel, path string) []treeViolation {
+	violations := []treeViolation{}
+	add := func(check, message string) {
+		violations = append(violations, treeViolation{Rule: rule.ID, Path: rel, Check: check, Message: message})
+	}
+
+	if rule.namingRegex != nil && !rule.namingRegex.MatchString(filepath.Base(rel)) {
+		add("naming_pattern", fmt.Sprintf("file name does not match %s", rule.NamingPattern))
+	}
+	if len(rule.AllowedDocTypes) == 0 && len(rule.RequiredFrontMatter) == 0 && len(rule.titleRegexes) == 0 {
+		return violations
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		add("read", err.Error())
+		return violations
+	}
+	frontMatter, body := splitFrontMatter(strings.ReplaceAll(string(data), "\r\n", "\n"))
+	for _, key := range rule.RequiredFrontMatter {
+		if strings.TrimSpace(frontMatter[strings.ToLower(key)]) == "" {
+			add("required_front_matter", fmt.Sprintf("missing front matter key %q", key))
+		}
+	}
+	if docType := strings.Trim(strings.TrimSpace(frontMatter["doc_type"]), `"'`); docType != "" && len(rule.AllowedDocTypes) > 0 {
+		allowed := false
+		for _, candidate := range rule.AllowedDocTypes {
+			if strings.EqualFold(candidate, docType) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			add("allowed_doc_types", fmt.Sprintf("doc_type %q is not allowed here (allowed: %s)", docType, strings.Join(rule.AllowedDocTypes, ", ")))
+		}
+	}
+	if title := firstTitle(body); title != "" {
+		for i, titleRegex := range rule.titleRegexes {
+			if titleRegex.MatchString(title) {
+				add("forbidden_title_patterns", fmt.Sprintf("title %q matches forbidden pattern %s", title, rule.ForbiddenTitlePatterns[i]))
+			}
+		}
+	}
+	return violations
+}
+
+// splitFrontMatter reads flat `key: value` pairs from a leading `---` block.
+func splitFrontMatter(content string) (map[string]string, string) {
+	values := map[string]string{}
+	if !strings.HasPrefix(content, "---\n") {
+		return values, content
+	}
+	rest := content[len("---\n"):]
+	end := strings.Index(rest, "\n---")
+	if end < 0 {
+		return values, content
+	}
+	for _, line := range strings.Split(rest[:end], "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.HasPrefix(line, " ") {
+			continue
+		}
+		values[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+	}
+	body := rest[end+len("\n---"):]
+	return values, strings.TrimPrefix(body, "\n")
+}
+
+func firstTitle(body string) string {
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "# ") {
+			return trimmed
+		}
+	}
+	return ""
+}
+
+func isMarkdown(path string) bool {
+	name := strings.ToLower(path)
+	return strings.HasSuffix(name, ".md") || strings.HasSuffix(name, ".markdown")
+}
+
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+func printBlocked(issues []string, details map[string]any) {
+	payload := map[string]any{
+		"status": "BLOCKED",
+		"issues": issues,
+	}
+	for k, v := range details {
+		payload[k] = v
+	}
+	data, _ := json.Marshal(payload)
+	fmt.Println(string(data))
+}
+
+// openapiLintCommand locates the openapi_lint source. Its --decode mode is the
+// repo's YAML reader, so the docs tree policy
+// gets full YAML without a second parser.
+func openapiLintCommand(root, explicit string) string {
+	if explicit != "" {
+		if filepath.IsAbs(explicit) {
+			return explicit
+		}
+		return filepath.Join(root, filepath.FromSlash(explicit))
+	}
+	rel := filepath.Join(".github", "skills", "local-mcp-setup", "cmd", "openapi_lint", "main.go")
+	if cwd, err := os.Getwd(); err == nil && exists(filepath.Join(cwd, rel)) {
+		return filepath.Join(cwd, rel)
+	}
+	return filepath.Join(root, rel)
+}
+
+// readYAMLPaths decodes a YAML file with openapi_lint --decode and flattens the
+// document into dotted paths. Sequence items are numbered from 0, so rules.0.glob is the
+// glob of the first rule.
+func readYAMLPaths(path, command string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if !exists(command) {
+		return nil, fmt.Errorf("openapi_lint command not found at %s (set --openapi-lint)", filepath.ToSlash(command))
+	}
+	cmd := exec.Command("go", "run", command, "--decode", filepath.Base(path))
+	cmd.Stdin = bytes.NewReader(data)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	var result struct {
+		Status   string   `json:"status"`
+		Issues   []string `json:"issues"`
+		Document any      `json:"document"`
+	}
+	if jsonErr := json.Unmarshal(output, &result); jsonErr != nil {
+		if err == nil {
+			err = jsonErr
+		}
+		return nil, fmt.Errorf("openapi_lint --decode: %v %s", err, strings.TrimSpace(stderr.String()))
+	}
+	if result.Status != "PASS" {
+		return nil, fmt.Errorf("%s", strings.Join(result.Issues, "; "))
+	}
+	values := map[string]string{}
+	flattenDocument(values, "", result.Document)
+	return values, nil
+}
+
+func flattenDocument(values map[string]string, path string, node any) {
+	child := func(key string) string {
+		if path == "" {
+			return key
+		}
+		return path + "." + key
+	}
+	switch typed := node.(type) {
+	case map[string]any:
+		for key, value := range typed {
+			flattenDocument(values, child(key), value)
+		}
+	case []any:
+		for i, value := range typed {
+			flattenDocument(values, child(strconv.Itoa(i)), value)
+		}
+	case string:
+		values[path] = typed
+	case bool:
+		values[path] = strconv.FormatBool(typed)
+	case float64:
+		values[path] = strconv.FormatFloat(typed, 'f', -1, 64)
+	}
+}
+
+// yamlList returns the scalars of a sequence at key, or the value itself when
+// key holds a single scalar.
+func yamlList(values map[string]string, key string) []string {
+	out := []string{}
+	if value := strings.TrimSpace(values[key]); value != "" {
+		return append(out, value)
+	}
+	for i := 0; ; i++ {
+		value, ok := values[key+"."+strconv.Itoa(i)]
+		if !ok {
+			return out
+		}
+		if value = strings.TrimSpace(value); value != "" {
+			out = append(out, value)
+		}
+	}
+}
+
+// yamlItems counts the mapping items of a block sequence at key.
+func yamlItems(values map[string]string, key string) int {
+	count := 0
+	for path := range values {
+		if !strings.HasPrefix(path, key+".") {
+			continue
+		}
+		index := strings.SplitN(strings.TrimPrefix(path, key+"."), ".", 2)[0]
+		if n, err := strconv.Atoi(index); err == nil && n+1 > count {
+			count = n + 1
+		}
+	}
+	return count
+}
@@ -6,40 +6,99 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
+	"time"
 )
 
-var blockedPathSubstrings = []string{
-	"phase-gate",
-	"implementation-plan",
-	"technology-constraints",
-	"traceability-matrix",
-	"repo-topology-decision",
-	"openapi-contract-plan",
-	"product-intent",
-	"requirements",
-	"backlog",
-	"severity-classification",
-	"adr",
-}
-
-var blockedTitleSubstrings = []string{
-	"# implementation plan",
-	"# technology constraints",
-	"# traceability matrix",
-	"# repo topology decision",
-	"# openapi contract plan",
-	"# product intent",
-	"# requirements",
-	"# backlog",
-	"# phase gate",
-	"# severity classification",
-	"# architecture decision record",
+// feedbackRules drives content classification. Files are judged by their
+// structure (front matter doc_type, headings, deliverable-style sections),
+// never by filename, so renaming a plan does not exempt it.
+type feedbackRules struct {
+	FeedbackDocTypes    []string            `json:"feedback_doc_types"`
+	DeliverableDocTypes []string            `json:"deliverable_doc_types"`
+	FeedbackHeadings    []string            `json:"feedback_headings"`
+	MinFeedbackHeadings int                 `json:"min_feedback_headings"`
+	DeliverableMarkers  []deliverableMarker `json:"deliverable_markers"`
+	BlockedTitles       []string            `json:"blocked_titles"`
+	ExemptFiles         []string            `json:"exempt_files"`
+}
+
+type deliverableMarker struct {
+	Kind    string `json:"kind"`
+	Pattern string `json:"pattern"`
+	Reason  string `json:"reason"`
+	regex   *regexp.Regexp
+}
+
+type violationDetail struct {
+	Path    string   `json:"path"`
+	Kind    string   `json:"kind,omitempty"`
+	Reasons []string `json:"reasons"`
+
+	structural bool
+}
+
+type relocation struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination,omitempty"`
+	Kind        string `json:"kind,omitempty"`
+	Action      string `json:"action"`
+	Stub        string `json:"stub,omitempty"`
+	Note        string `json:"note,omitempty"`
+}
+
+type relocationReport struct {
+	TimestampUTC string       `json:"timestamp_utc"`
+	Mode         string       `json:"mode"`
+	Relocations  []relocation `json:"relocations"`
+}
+
+const (
+	relocationReportPath  = "docs/tooling/feedback-relocation-report.json"
+	aggregateRequirements = "docs/requirements.md"
+)
+
+func defaultRules() feedbackRules {
+	return feedbackRules{
+		FeedbackDocTypes: []string{"feedback", "issue", "finding", "report", "review"},
+		DeliverableDocTypes: []string{
+			"plan", "implementation-plan", "requirements", "adr", "technology-constraints",
+			"traceability-matrix", "repo-topology-decision", "openapi-contract-plan",
+			"product-intent", "backlog", "phase-gate", "severity-classification",
+		},
+		FeedbackHeadings:    []string{"summary", "context", "observation", "observations", "finding", "findings", "issue", "impact", "recommendation", "recommendations", "feedback", "suggested action", "next steps"},
+		MinFeedbackHeadings: 1,
+		DeliverableMarkers: []deliverableMarker{
+			{Kind: "adr", Pattern: `(?im)^#{1,3}\s*status\s*$[\s\S]{0,200}?^\s*[-*]?\s*(proposed|accepted|superseded|deprecated|rejected)\b`, Reason: "contains an ADR status block"},
+			{Kind: "adr", Pattern: `(?im)^#{1,3}\s*(decision|consequences)\s*$`, Reason: "contains ADR decision/consequences sections"},
+			{Kind: "requirements", Pattern: `(?im)^\|\s*(req(uirement)?[ _-]?id)\s*\|`, Reason: "contains a requirement ID table"},
+			{Kind: "requirements", Pattern: `(?m)^\|\s*REQ-\d+\s*\|`, Reason: "contains requirement rows (REQ-###)"},
+			{Kind: "plan", Pattern: `(?im)^#{1,3}\s*(milestones|work breakdown|implementation steps|rollout plan)\s*$`, Reason: "contains implementation plan sections"},
+		},
+		BlockedTitles: []string{
+			"# implementation plan",
+			"# technology constraints",
+			"# traceability matrix",
+			"# repo topology decision",
+			"# openapi contract plan",
+			"# product intent",
+			"# requirements",
+			"# backlog",
+			"# phase gate",
+			"# severity classification",
+			"# architecture decision record",
+		},
+		ExemptFiles: []string{"readme.md"},
+	}
 }
 
 func main() {
 	targetRoot := flag.String("target-root", "", "target repository root (defaults to cwd)")
+	rulesFile := flag.String("rules-file", "docs/tooling/feedback-tree-rules.json", "JSON classification rules; a missing default file uses built-in rules")
+	fix := flag.Bool("fix", false, "propose moves of misplaced deliverables to their canonical locations")
+	confirm := flag.Bool("confirm", false, "with --fix, perform the proposed moves and leave stub feedback notes")
 	flag.Parse()
 
 	root := strings.TrimSpace(*targetRoot)
@@ -56,14 +115,24 @@ func main() {
 		printBlocked([]string{"invalid target root"}, nil)
 		return
 	}
+	if *confirm && !*fix {
+		printBlocked([]string{"--confirm requires --fix"}, nil)
+		return
+	}
+
+	rules, rulesPath, err := loadRules(absRoot, strings.TrimSpace(*rulesFile), flagSet("rules-file"))
+	if err != nil {
+		printBlocked([]string{fmt.Sprintf("invalid feedback tree rules: %v", err)}, nil)
+		return
+	}
 
 	feedbackRoot := filepath.Join(absRoot, "docs", "feedback")
 	if info, statErr := os.Stat(feedbackRoot); statErr != nil || !info.IsDir() {
-		printPass(feedbackRoot)
+		printPass(feedbackRoot, rulesPath, nil)
 		return
 	}
 
-	violations := []string{}
+	details := []violationDetail{}
 	walkErr := filepath.WalkDir(feedbackRoot, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
 			return nil
@@ -71,10 +140,7 @@ func main() {
 		if d.IsDir() {
 			return nil
 		}
-		if !isMarkdown(path) {
-			return nil
-		}
-		if looksLikeFeedbackFile(path) {
+		if !isMarkdown(path) || rules.exempt(path) {
 			return nil
 		}
 
@@ -82,21 +148,13 @@ func main() {
 		if relErr != nil {
 			rel = path
 		}
-		relSlash := strings.ToLower(filepath.ToSlash(rel))
-		for _, token := range blockedPathSubstrings {
-			if strings.Contains(relSlash, token) {
-				violations = append(violations, filepath.ToSlash(rel))
-				return nil
-			}
+		data, readErr := os.ReadFile(path)
+		if readErr != nil {
+			return nil
 		}
-
-		head := readHead(path, 40)
-		headLower := strings.ToLower(head)
-		for _, token := range blockedTitleSubstrings {
-			if strings.Contains(headLower, token) {
-				violations = append(violations, filepath.ToSlash(rel))
-				return nil
-			}
+		kind, structural, reasons := classify(string(data), rules)
+		if len(reasons) > 0 {
+			details = append(details, violationDetail{Path: filepath.ToSlash(rel), Kind: kind, Reasons: reasons, structural: structural})
 		}
 		return nil
 	})
@@ -105,61 +163,397 @@ func main() {
 		return
 	}
 
-	if len(violations) > 0 {
-		sort.Strings(violations)
-		printBlocked([]string{"docs/feedback contains non-feedback draft deliverables"}, violations)
+	sort.Slice(details, func(i, j int) bool { return details[i].Path < details[j].Path })
+
+	extra := map[string]any{}
+	if *fix && len(details) > 0 {
+		relocations := planRelocations(absRoot, details)
+		mode := "proposed"
+		if *confirm {
+			mode = "applied"
+			relocations = applyRelocations(absRoot, relocations)
+			details = remainingViolations(details, relocations)
+		}
+		reportPath := filepath.Join(absRoot, filepath.FromSlash(relocationReportPath))
+		if err := writeRelocationReport(reportPath, relocationReport{TimestampUTC: time.Now().UTC().Format(time.RFC3339), Mode: mode, Relocations: relocations}); err != nil {
+			printBlocked([]string{fmt.Sprintf("failed to write relocation report: %v", err)}, map[string]any{"relocations": relocations})
+			return
+		}
+		extra["relocations"] = relocations
+		extra["relocation_report"] = filepath.ToSlash(reportPath)
+	}
+
+	if len(details) > 0 {
+		violations := make([]string, 0, len(details))
+		for _, detail := range details {
+			violations = append(violations, detail.Path)
+		}
+		extra["violations"] = violations
+		extra["violation_details"] = details
+		extra["rules_file"] = filepath.ToSlash(rulesPath)
+		printBlocked([]string{"docs/feedback contains non-feedback draft deliverables"}, extra)
 		return
 	}
 
-	printPass(feedbackRoot)
+	printPass(feedbackRoot, rulesPath, extra)
 }
 
-func isMarkdown(path string) bool {
-	name := strings.ToLower(path)
-	return strings.HasSuffix(name, ".md") || strings.HasSuffix(name, ".markdown")
+// canonicalDestination maps a deliverable kind to where it belongs. Kinds
+// without a canonical home are reported but never moved, and requirements
+// drafts are only proposed because docs/requirements.md is a single aggregate
+// document that has to be merged by hand.
+func canonicalDestination(kind, source string) string {
+	base := filepath.Base(source)
+	switch kind {
+	case "requirements":
+		return aggregateRequirements
+	case "adr":
+		return "docs/adr/" + base
+	case "plan", "implementation-plan":
+		return "docs/plans/" + base
+	}
+	return ""
 }
 
-func looksLikeFeedbackFile(path string) bool {
-	base := strings.ToLower(filepath.Base(path))
-	if base == "readme.md" {
-		return true
+func planRelocations(root string, details []violationDetail) []relocation {
+	relocations := []relocation{}
+	claimed := map[string]string{}
+	for _, detail := range details {
+		move := relocation{Source: detail.Path, Kind: detail.Kind}
+		move.Destination = canonicalDestination(detail.Kind, detail.Path)
+		switch {
+		case move.Destination == "":
+			move.Action = "skip"
+			move.Note = "no canonical location for this deliverable kind"
+		case !detail.structural:
+			move.Action = "skip"
+			move.Note = "classified by title only; move it by hand if it is a deliverable"
+		case move.Destination == aggregateRequirements:
+			move.Action = "skip"
+			move.Note = "merge into " + aggregateRequirements + " by hand"
+		case exists(filepath.Join(root, filepath.FromSlash(move.Destination))):
+			move.Action = "skip"
+			move.Note = "conflict: destination already exists"
+		case claimed[move.Destination] != "":
+			move.Action = "skip"
+			move.Note = "conflict: destination already claimed by " + claimed[move.Destination]
+		default:
+			move.Action = "move"
+			move.Stub = detail.Path
+			claimed[move.Destination] = detail.Path
+		}
+		relocations = append(relocations, move)
+	}
+	return relocations
+}
+
+// applyRelocations moves each planned file and rewrites the source path as a
+// feedback note that links to the new location.
+func applyRelocations(root string, relocations []relocation) []relocation {
+	for i, move := range relocations {
+		if move.Action != "move" {
+			continue
+		}
+		source := filepath.Join(root, filepath.FromSlash(move.Source))
+		destination := filepath.Join(root, filepath.FromSlash(move.Destination))
+		if err := os.MkdirAll(filepath.Dir(destination), 0o755); err != nil {
+			relocations[i].Action, relocations[i].Stub, relocations[i].Note = "failed", "", err.Error()
+			continue
+		}
+		if err := os.Rename(source, destination); err != nil {
+			relocations[i].Action, relocations[i].Stub, relocations[i].Note = "failed", "", err.Error()
+			continue
+		}
+		if err := os.WriteFile(source, []byte(stubNote(move, filepath.Dir(source), destination)), 0o644); err != nil {
+			relocations[i].Action, relocations[i].Stub = "moved", ""
+			relocations[i].Note = "stub not written: " + err.Error()
+			continue
+		}
+		relocations[i].Action = "moved"
+	}
+	return relocations
+}
+
+func stubNote(move relocation, sourceDir, destination string) string {
+	link, err := filepath.Rel(sourceDir, destination)
+	if err != nil {
+		link = move.Destination
+	}
+	return strings.Join([]string{
+		"---",
+		"doc_type: feedback",
+		"relocated_to: " + move.Destination,
+		"---",
+		"# Relocated deliverable",
+		"",
+		"## Summary",
+		fmt.Sprintf("This %s draft was moved out of `docs/feedback/` by `feedback_tree_policy_lint --fix --confirm`.", move.Kind),
+		"",
+		fmt.Sprintf("- New location: [%s](%s)", move.Destination, filepath.ToSlash(link)),
+		fmt.Sprintf("- Relocated (UTC): %s", time.Now().UTC().Format(time.RFC3339)),
+	}, "\n") + "\n"
+}
+
+func remainingViolations(details []violationDetail, relocations []relocation) []violationDetail {
+	moved := map[string]bool{}
+	for _, move := range relocations {
+		if move.Action == "moved" {
+			moved[move.Source] = true
+		}
+	}
+	remaining := []violationDetail{}
+	for _, detail := range details {
+		if !moved[detail.Path] {
+			remaining = append(remaining, detail)
+		}
+	}
+	return remaining
+}
+
+func writeRelocationReport(path string, report relocationReport) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func loadRules(root, value string, explicit bool) (feedbackRules, string, error) {
+	rules := defaultRules()
+	path := ""
+	if value != "" {
+		path = value
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(root, filepath.FromSlash(path))
+		}
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			// Keys present in the file replace the matching built-in rule list.
+			if err := json.Unmarshal(data, &rules); err != nil {
+				return rules, path, err
+			}
+		case explicit:
+			return rules, path, err
+		default:
+			path = ""
+		}
 	}
-	feedbackTokens := []string{"feedback", "issue", "finding", "report"}
-	for _, token := range feedbackTokens {
-		if strings.Contains(base, token) {
+	for i := range rules.DeliverableMarkers {
+		compiled, err := regexp.Compile(rules.DeliverableMarkers[i].Pattern)
+		if err != nil {
+			return rules, path, fmt.Errorf("deliverable marker %q: %v", rules.DeliverableMarkers[i].Kind, err)
+		}
+		rules.DeliverableMarkers[i].regex = compiled
+	}
+	return rules, path, nil
+}
+
+func (r feedbackRules) exempt(path string) bool {
+	base := strings.ToLower(filepath.Base(path))
+	for _, name := range r.ExemptFiles {
+		if base == strings.ToLower(strings.TrimSpace(name)) {
 			return true
 		}
 	}
 	return false
 }
 
-func readHead(path string, maxLines int) string {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return ""
+// classify returns the deliverable kind and the reasons a file does not belong
+// under docs/feedback. No reasons means the file is a feedback note. Markers
+// are checked even when doc_type claims feedback, so a plan cannot pass by
+// relabelling its front matter. Blocked titles only match the level-1 title,
+// and a feedback doc_type with the required feedback headings outweighs a
+// title match. structural reports whether the kind came from doc_type or a
+// marker rather than from the title alone.
+func classify(content string, rules feedbackRules) (kind string, structural bool, reasons []string) {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	frontMatter, body := splitFrontMatter(content)
+	docType := strings.ToLower(strings.Trim(strings.TrimSpace(frontMatter["doc_type"]), `"'`))
+	feedbackType := docType != "" && containsFold(rules.FeedbackDocTypes, docType)
+	feedbackHeadings := countFeedbackHeadings(body, rules.FeedbackHeadings) >= rules.MinFeedbackHeadings
+
+	reasons = []string{}
+	if docType != "" && containsFold(rules.DeliverableDocTypes, docType) {
+		kind, structural = docType, true
+		reasons = append(reasons, fmt.Sprintf("front matter doc_type %q is a deliverable type", docType))
+	}
+
+	for _, marker := range rules.DeliverableMarkers {
+		if marker.regex.MatchString(body) {
+			if kind == "" {
+				kind, structural = marker.Kind, true
+			}
+			reason := marker.Reason
+			if strings.TrimSpace(reason) == "" {
+				reason = fmt.Sprintf("matches %s deliverable marker", marker.Kind)
+			}
+			reasons = appendUnique(reasons, reason)
+		}
+	}
+
+	if title := documentTitle(body); title != "" && !(feedbackType && feedbackHeadings) {
+		for _, blocked := range rules.BlockedTitles {
+			if strings.EqualFold(title, titleText(blocked)) {
+				if kind == "" {
+					kind = titleKind(blocked)
+				}
+				reasons = append(reasons, fmt.Sprintf("title matches deliverable heading %q", blocked))
+				break
+			}
+		}
+	}
+
+	if feedbackType {
+		if len(reasons) > 0 {
+			reasons = append([]string{fmt.Sprintf("front matter doc_type %q conflicts with deliverable structure", docType)}, reasons...)
+		}
+		return kind, structural, reasons
+	}
+	if len(reasons) == 0 && !feedbackHeadings {
+		reasons = append(reasons, "no feedback doc_type in front matter and no feedback headings")
+	}
+	return kind, structural, reasons
+}
+
+// documentTitle returns the text of the first level-1 heading outside fenced
+// code blocks.
+func documentTitle(body string) string {
+	fenced := false
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			fenced = !fenced
+			continue
+		}
+		if fenced {
+			continue
+		}
+		if match := titlePattern.FindStringSubmatch(line); match != nil {
+			return strings.TrimSpace(match[1])
+		}
+	}
+	return ""
+}
+
+var titlePattern = regexp.MustCompile(`^#\s+(.+?)\s*#*\s*$`)
+
+// titleText strips the leading "#" markers from a blocked title rule.
+func titleText(title string) string {
+	return strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(title), "#"))
+}
+
+// titleKind maps the blocked titles that have a canonical home to a kind.
+func titleKind(title string) string {
+	switch strings.ToLower(titleText(title)) {
+	case "requirements":
+		return "requirements"
+	case "architecture decision record":
+		return "adr"
+	case "implementation plan":
+		return "plan"
+	}
+	return ""
+}
+
+// splitFrontMatter reads flat `key: value` pairs from a leading `---` block.
+func splitFrontMatter(content string) (map[string]string, string) {
+	values := map[string]string{}
+	if !strings.HasPrefix(content, "---\n") {
+		return values, content
+	}
+	rest := content[len("---\n"):]
+	end := strings.Index(rest, "\n---")
+	if end < 0 {
+		return values, content
+	}
+	for _, line := range strings.Split(rest[:end], "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.HasPrefix(line, " ") {
+			continue
+		}
+		values[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
 	}
-	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
-	if len(lines) > maxLines {
-		lines = lines[:maxLines]
+	body := rest[end+len("\n---"):]
+	return values, strings.TrimPrefix(body, "\n")
+}
+
+func countFeedbackHeadings(body string, headings []string) int {
+	count := 0
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "##") {
+			continue
+		}
+		text := strings.ToLower(strings.TrimSpace(strings.TrimLeft(trimmed, "#")))
+		text = strings.TrimRight(text, ":")
+		if containsFold(headings, text) {
+			count++
+		}
+	}
+	return count
+}
+
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(strings.TrimSpace(value), target) {
+			return true
+		}
+	}
+	return false
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
 	}
-	return strings.Join(lines, "\n")
+	return append(values, value)
 }
 
-func printPass(root string) {
-	payload, _ := json.Marshal(map[string]any{
+func isMarkdown(path string) bool {
+	name := strings.ToLower(path)
+	return strings.HasSuffix(name, ".md") || strings.HasSuffix(name, ".markdown")
+}
+
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+func printPass(root, rulesPath string, details map[string]any) {
+	payload := map[string]any{
 		"status":        "PASS",
 		"feedback_root": filepath.ToSlash(root),
-	})
-	fmt.Println(string(payload))
+		"rules_file":    filepath.ToSlash(rulesPath),
+	}
+	for k, v := range details {
+		payload[k] = v
+	}
+	data, _ := json.Marshal(payload)
+	fmt.Println(string(data))
 }
 
-func printBlocked(issues []string, violations []string) {
+func printBlocked(issues []string, details map[string]any) {
 	payload := map[string]any{
 		"status": "BLOCKED",
 		"issues": issues,
 	}
-	if len(violations) > 0 {
-		payload["violations"] = violations
+	for k, v := range details {
+		payload[k] = v
 	}
 	data, _ := json.Marshal(payload)
 	fmt.Println(string(data))
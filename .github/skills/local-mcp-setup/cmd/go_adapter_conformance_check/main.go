@@ -0,0 +1,618 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultDriverPackages lists the database and cache clients whose error types
+// adapters must map to domain errors.
+const defaultDriverPackages = "database/sql,github.com/jackc/pgx,github.com/jackc/pgconn,github.com/lib/pq," +
+	"github.com/go-sql-driver/mysql,github.com/jmoiron/sqlx,gorm.io/gorm,go.mongodb.org/mongo-driver," +
+	"github.com/redis/go-redis,github.com/go-redis/redis,github.com/gomodule/redigo,github.com/gocql/gocql," +
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+
+// contextExemptMethods satisfy well-known interfaces whose signatures are
+// fixed, so they cannot take context.Context first.
+var contextExemptMethods = map[string]bool{
+	"ServeHTTP": true, "String": true, "GoString": true, "Error": true, "Format": true, "Unwrap": true,
+	"Is": true, "As": true, "MarshalJSON": true, "UnmarshalJSON": true, "MarshalText": true,
+	"UnmarshalText": true, "MarshalBinary": true, "UnmarshalBinary": true, "MarshalYAML": true,
+	"UnmarshalYAML": true, "Scan": true, "Value": true, "Read": true, "Write": true, "Len": true,
+	"Less": true, "Swap": true,
+}
+
+var adapterDirectivePattern = regexp.MustCompile(`(?i)^(adapter_id|adaptor_id)\s*:\s*([a-zA-Z0-9._-]+)`)
+
+type conformanceFinding struct {
+	Rule    string `json:"rule"`
+	Repo    string `json:"repo"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+type goPackage struct {
+	dir        string
+	importPath string
+	adapter    bool
+	domain     bool
+	files      []*parsedFile
+}
+
+type parsedFile struct {
+	rel  string
+	fset *token.FileSet
+	ast  *ast.File
+}
+
+func main() {
+	targetRoot := flag.String("target-root", "", "target repository root (defaults to cwd)")
+	domainDirs := flag.String("domain-dirs", "domain,core", "comma-separated path segments that mark domain packages")
+	adapterDirs := flag.String("adapter-dirs", "adapters,adapter", "comma-separated path segments that mark adapter packages")
+	driverPackages := flag.String("driver-packages", defaultDriverPackages, "comma-separated import path prefixes whose errors must not cross the adapter boundary")
+	flag.Parse()
+
+	root := strings.TrimSpace(*targetRoot)
+	if root == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			printBlocked([]string{"unable to determine working directory"}, nil)
+			return
+		}
+		root = cwd
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		printBlocked([]string{"invalid target root"}, nil)
+		return
+	}
+
+	reposRoot := filepath.Join(absRoot, "repos")
+	entries, err := os.ReadDir(reposRoot)
+	if err != nil {
+		printBlocked([]string{"repos/ workspace not found"}, nil)
+		return
+	}
+
+	domainSegments := splitList(*domainDirs)
+	adapterSegments := splitList(*adapterDirs)
+	drivers := splitList(*driverPackages)
+
+	checked := []string{}
+	findings := []conformanceFinding{}
+	issues := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		repoRel := "repos/" + entry.Name()
+		repoPath := filepath.Join(reposRoot, entry.Name())
+		module, packages, parseIssues := loadGoPackages(repoPath, domainSegments, adapterSegments)
+		issues = append(issues, prefixAll(repoRel, parseIssues)...)
+		if len(packages) == 0 {
+			continue
+		}
+		checked = append(checked, repoRel)
+		findings = append(findings, checkRepo(repoRel, module, packages, adapterSegments, drivers)...)
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].File != findings[j].File {
+			return findings[i].File < findings[j].File
+		}
+		return findings[i].Line < findings[j].Line
+	})
+
+	details := map[string]any{
+		"repos_checked": checked,
+		"findings":      findings,
+	}
+	if len(issues) > 0 {
+		details["parse_issues"] = issues
+	}
+	if len(findings) > 0 {
+		printBlocked([]string{"port/adapter conformance findings"}, details)
+		return
+	}
+
+	details["status"] = "PASS"
+	payload, _ := json.Marshal(details)
+	fmt.Println(string(payload))
+}
+
+func splitList(value string) []string {
+	out := []string{}
+	for _, part := range strings.Split(value, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+func prefixAll(prefix string, values []string) []string {
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		out = append(out, prefix+": "+value)
+	}
+	return out
+}
+
+func modulePath(repoPath string) string {
+	f, err := os.Open(filepath.Join(repoPath, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], "\"")
+		}
+	}
+	return ""
+}
+
+// loadGoPackages parses every non-test Go file in a repo and groups the files
+// by directory. Directories are classified as adapter or domain packages from
+// their path segments or an `// adapter_id:` directive.
+func loadGoPackages(repoPath string, domainSegments, adapterSegments []string) (string, map[string]*goPackage, []string) {
+	module := modulePath(repoPath)
+	packages := map[string]*goPackage{}
+	issues := []string{}
+	_ = filepath.WalkDir(repoPath, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != repoPath && (strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules" || name == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(d.Name(), ".go") || strings.HasSuffix(d.Name(), "_test.go") {
+			return nil
+		}
+		rel, _ := filepath.Rel(repoPath, path)
+		fset := token.NewFileSet()
+		file, parseErr := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if parseErr != nil {
+			issues = append(issues, fmt.Sprintf("failed to parse %s: %v", filepath.ToSlash(rel), parseErr))
+			return nil
+		}
+		dir := filepath.ToSlash(filepath.Dir(rel))
+		pkg, ok := packages[dir]
+		if !ok {
+			importPath := module
+			if dir != "." && module != "" {
+				importPath = module + "/" + dir
+			}
+			pkg = &goPackage{dir: dir, importPath: importPath}
+			pkg.adapter = hasSegment(dir, adapterSegments)
+			pkg.domain = !pkg.adapter && hasSegment(dir, domainSegments)
+			packages[dir] = pkg
+		}
+		if !pkg.adapter && hasAdapterDirective(file) {
+			pkg.adapter = true
+			pkg.domain = false
+		}
+		pkg.files = append(pkg.files, &parsedFile{rel: filepath.ToSlash(rel), fset: fset, ast: file})
+		return nil
+	})
+	return module, packages, issues
+}
+
+func hasSegment(dir string, segments []string) bool {
+	for _, part := range strings.Split(strings.ToLower(dir), "/") {
+		for _, segment := range segments {
+			if part == segment {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasAdapterDirective(file *ast.File) bool {
+	for _, group := range file.Comments {
+		if group.Pos() > file.Package {
+			break
+		}
+		for _, line := range strings.Split(group.Text(), "\n") {
+			if adapterDirectivePattern.MatchString(strings.TrimSpace(line)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func checkRepo(repo, module string, packages map[string]*goPackage, adapterSegments, drivers []string) []conformanceFinding {
+	adapterImports := map[string]bool{}
+	for _, pkg := range packages {
+		if pkg.adapter && pkg.importPath != "" {
+			adapterImports[pkg.importPath] = true
+		}
+	}
+
+	findings := []conformanceFinding{}
+	add := func(rule string, file *parsedFile, pos token.Pos, message string) {
+		findings = append(findings, conformanceFinding{
+			Rule:    rule,
+			Repo:    repo,
+			File:    repo + "/" + file.rel,
+			Line:    file.fset.Position(pos).Line,
+			Message: message,
+		})
+	}
+
+	for _, pkg := range packages {
+		fields := map[string]bool{}
+		if pkg.adapter {
+			fields = driverFields(pkg, drivers)
+		}
+		for _, file := range pkg.files {
+			imports := importAliases(file.ast)
+			if pkg.domain {
+				for _, spec := range file.ast.Imports {
+					path, _ := strconv.Unquote(spec.Path.Value)
+					local := module != "" && (path == module || strings.HasPrefix(path, module+"/"))
+					if adapterImports[path] || (local && hasSegment(strings.TrimPrefix(path, module), adapterSegments)) {
+						add("domain-imports-adapter", file, spec.Pos(), fmt.Sprintf("domain package %s imports adapter package %s", pkg.dir, path))
+					}
+				}
+			}
+			if !pkg.adapter {
+				continue
+			}
+			for _, decl := range file.ast.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || !fn.Name.IsExported() {
+					continue
+				}
+				if fn.Recv != nil && receiverExported(fn.Recv) && !contextExempt(fn, imports) && !contextFirst(fn.Type, imports) {
+					add("adapter-context-first", file, fn.Pos(), fmt.Sprintf("exported adapter method %s must take context.Context as its first parameter", fn.Name.Name))
+				}
+				if fn.Recv != nil && !receiverExported(fn.Recv) {
+					continue
+				}
+				for _, leak := range driverErrorLeaks(fn, imports, drivers, fields) {
+					add("adapter-leaks-driver-error", file, leak.pos, fmt.Sprintf("exported adapter function %s exposes driver error %s; map it to a domain error", fn.Name.Name, leak.name))
+				}
+			}
+		}
+	}
+	return findings
+}
+
+// importAliases maps the local name of each import to its path.
+func importAliases(file *ast.File) map[string]string {
+	out := map[string]string{}
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		name := defaultImportName(path)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if name == "_" || name == "." {
+			continue
+		}
+		out[name] = path
+	}
+	return out
+}
+
+// defaultImportName derives the package name of an unaliased import without
+// type-checking: a /vN major-version element and a gopkg.in ".vN" suffix are
+// dropped, so github.com/jackc/pgx/v5 is pgx and gopkg.in/yaml.v3 is yaml.
+func defaultImportName(path string) string {
+	parts := strings.Split(path, "/")
+	name := parts[len(parts)-1]
+	if len(parts) > 1 && isMajorVersion(name) {
+		name = parts[len(parts)-2]
+	}
+	if strings.HasPrefix(path, "gopkg.in/") {
+		if i := strings.LastIndex(name, ".v"); i > 0 && isMajorVersion(name[i+1:]) {
+			name = name[:i]
+		}
+	}
+	name = strings.TrimPrefix(name, "go-")
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+func isMajorVersion(value string) bool {
+	if len(value) < 2 || value[0] != 'v' {
+		return false
+	}
+	_, err := strconv.Atoi(value[1:])
+	return err == nil
+}
+
+// driverFields collects the names of struct fields, across a package, whose
+// type comes from a driver package (db *sql.DB, pool *pgxpool.Pool).
+func driverFields(pkg *goPackage, drivers []string) map[string]bool {
+	out := map[string]bool{}
+	for _, file := range pkg.files {
+		imports := importAliases(file.ast)
+		ast.Inspect(file.ast, func(node ast.Node) bool {
+			st, ok := node.(*ast.StructType)
+			if !ok {
+				return true
+			}
+			for _, field := range st.Fields.List {
+				if _, ok := driverType(field.Type, imports, drivers); !ok {
+					continue
+				}
+				for _, name := range field.Names {
+					out[name.Name] = true
+				}
+			}
+			return true
+		})
+	}
+	return out
+}
+
+// driverType resolves a (possibly pointer or slice) type expression to a
+// qualified driver identifier such as database/sql.DB.
+func driverType(expr ast.Expr, imports map[string]string, drivers []string) (string, bool) {
+	for {
+		switch t := expr.(type) {
+		case *ast.StarExpr:
+			expr = t.X
+			continue
+		case *ast.ArrayType:
+			expr = t.Elt
+			continue
+		}
+		break
+	}
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return "", false
+	}
+	path, ok := imports[pkg.Name]
+	if !ok || !isDriverPackage(path, drivers) {
+		return "", false
+	}
+	return path + "." + sel.Sel.Name, true
+}
+
+func receiverExported(recv *ast.FieldList) bool {
+	if recv == nil || len(recv.List) == 0 {
+		return false
+	}
+	expr := recv.List[0].Type
+	for {
+		switch t := expr.(type) {
+		case *ast.StarExpr:
+			expr = t.X
+		case *ast.IndexExpr:
+			expr = t.X
+		case *ast.IndexListExpr:
+			expr = t.X
+		case *ast.Ident:
+			return t.IsExported()
+		default:
+			return false
+		}
+	}
+}
+
+// contextExempt reports whether a method is excused from the context-first
+// rule: it implements a well-known interface or takes an *http.Request, whose
+// Context carries the request context.
+func contextExempt(fn *ast.FuncDecl, imports map[string]string) bool {
+	if contextExemptMethods[fn.Name.Name] {
+		return true
+	}
+	if fn.Type.Params == nil {
+		return false
+	}
+	for _, field := range fn.Type.Params.List {
+		star, ok := field.Type.(*ast.StarExpr)
+		if !ok {
+			continue
+		}
+		sel, ok := star.X.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Request" {
+			continue
+		}
+		if pkg, ok := sel.X.(*ast.Ident); ok && imports[pkg.Name] == "net/http" {
+			return true
+		}
+	}
+	return false
+}
+
+// contextFirst reports whether a function takes context.Context first.
+// Methods without parameters (Close, Name) are not I/O entry points and pass.
+func contextFirst(fnType *ast.FuncType, imports map[string]string) bool {
+	if fnType.Params == nil || len(fnType.Params.List) == 0 {
+		return true
+	}
+	sel, ok := fnType.Params.List[0].Type.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Context" {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && imports[pkg.Name] == "context"
+}
+
+// isDriverPackage reports whether an import is one of the configured driver
+// packages or a subpackage of one (github.com/jackc/pgx covers pgx/v5/pgxpool).
+func isDriverPackage(path string, drivers []string) bool {
+	path = strings.ToLower(path)
+	for _, driver := range drivers {
+		if path == driver || strings.HasPrefix(path, driver+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+type driverLeak struct {
+	name string
+	pos  token.Pos
+}
+
+// driverErrorLeaks reports driver error values an exported adapter function
+// hands back to its caller: driver error types in the signature, driver
+// sentinels (sql.ErrNoRows, pgx.ErrNoRows) in return statements, error
+// variables assigned from driver calls and driver calls returned directly.
+// Driver calls are calls on driver packages, on driver-typed parameters and
+// locals, on driver-typed struct fields and on values those calls returned.
+func driverErrorLeaks(fn *ast.FuncDecl, imports map[string]string, drivers []string, fields map[string]bool) []driverLeak {
+	driverSelector := func(expr ast.Expr) (string, bool) {
+		name, ok := driverType(expr, imports, drivers)
+		if !ok || !strings.Contains(name[strings.LastIndex(name, ".")+1:], "Err") {
+			return "", false
+		}
+		return name, true
+	}
+
+	leaks := []driverLeak{}
+	returnsError := false
+	driverVars := map[string]bool{}
+	if fn.Type.Results != nil {
+		for _, field := range fn.Type.Results.List {
+			if name, ok := driverSelector(field.Type); ok {
+				leaks = append(leaks, driverLeak{name: name, pos: field.Pos()})
+			}
+			ident, ok := field.Type.(*ast.Ident)
+			returnsError = ok && ident.Name == "error"
+		}
+	}
+	if fn.Type.Params != nil {
+		for _, field := range fn.Type.Params.List {
+			if _, ok := driverType(field.Type, imports, drivers); ok {
+				for _, name := range field.Names {
+					driverVars[name.Name] = true
+				}
+			}
+		}
+	}
+	if fn.Body == nil {
+		return leaks
+	}
+
+	var driverValue func(expr ast.Expr) bool
+	driverCall := func(expr ast.Expr) (string, bool) {
+		call, ok := expr.(*ast.CallExpr)
+		if !ok {
+			return "", false
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return "", false
+		}
+		if pkg, ok := sel.X.(*ast.Ident); ok && !driverVars[pkg.Name] {
+			if path, ok := imports[pkg.Name]; ok && isDriverPackage(path, drivers) {
+				return path + "." + sel.Sel.Name, true
+			}
+		}
+		if driverValue(sel.X) {
+			return sel.Sel.Name, true
+		}
+		return "", false
+	}
+	driverValue = func(expr ast.Expr) bool {
+		switch e := expr.(type) {
+		case *ast.Ident:
+			return driverVars[e.Name]
+		case *ast.SelectorExpr:
+			return fields[e.Sel.Name]
+		case *ast.ParenExpr:
+			return driverValue(e.X)
+		case *ast.CallExpr:
+			_, ok := driverCall(e)
+			return ok
+		}
+		return false
+	}
+
+	errorVars := map[string]string{}
+	ast.Inspect(fn.Body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ValueSpec:
+			if _, ok := driverType(n.Type, imports, drivers); ok {
+				for _, name := range n.Names {
+					driverVars[name.Name] = true
+				}
+			}
+		case *ast.AssignStmt:
+			call := ""
+			fromDriver := false
+			if len(n.Rhs) == 1 {
+				call, fromDriver = driverCall(n.Rhs[0])
+			}
+			for i, lhs := range n.Lhs {
+				ident, ok := lhs.(*ast.Ident)
+				if !ok || ident.Name == "_" {
+					continue
+				}
+				delete(errorVars, ident.Name)
+				if !fromDriver {
+					delete(driverVars, ident.Name)
+					continue
+				}
+				if i == len(n.Lhs)-1 && strings.Contains(strings.ToLower(ident.Name), "err") {
+					errorVars[ident.Name] = call
+				} else {
+					driverVars[ident.Name] = true
+				}
+			}
+		case *ast.ReturnStmt:
+			for i, result := range n.Results {
+				if name, ok := driverSelector(result); ok {
+					leaks = append(leaks, driverLeak{name: name, pos: result.Pos()})
+					continue
+				}
+				if ident, ok := result.(*ast.Ident); ok {
+					if call, ok := errorVars[ident.Name]; ok {
+						leaks = append(leaks, driverLeak{name: fmt.Sprintf("%s (from %s)", ident.Name, call), pos: result.Pos()})
+					}
+					continue
+				}
+				if call, ok := driverCall(result); ok && returnsError && i == len(n.Results)-1 {
+					leaks = append(leaks, driverLeak{name: "returned by " + call, pos: result.Pos()})
+				}
+			}
+		}
+		return true
+	})
+	return leaks
+}
+
+func printBlocked(issues []string, details map[string]any) {
+	payload := map[string]any{
+		"status": "BLOCKED",
+		"issues": issues,
+	}
+	for k, v := range details {
+		payload[k] = v
+	}
+	data, _ := json.Marshal(payload)
+	fmt.Println(string(data))
+}
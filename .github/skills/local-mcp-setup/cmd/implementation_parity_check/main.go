@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"go/parser"
+	"go/token"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -14,68 +16,155 @@ import (
 
 var adapterLinePattern = regexp.MustCompile(`(?i)(adapter_id|adaptor_id)\s*:\s*([a-zA-Z0-9._-]+)`)
 
+var adapterRepoPattern = regexp.MustCompile(`(?i)\brepo\s*:\s*([^\s|,;` + "`" + `]+)`)
+
+var adapterDirectivePattern = regexp.MustCompile(`(?i)^(adapter_id|adaptor_id)\s*:\s*([a-zA-Z0-9._-]+)`)
+
+type declaredAdapter struct {
+	ID   string
+	Repo string
+}
+
+type repoParity struct {
+	Repo        string            `json:"repo"`
+	Declared    []string          `json:"declared"`
+	Implemented []string          `json:"implemented"`
+	Missing     []string          `json:"missing"`
+	Undeclared  []string          `json:"undeclared"`
+	Allowlisted []string          `json:"allowlisted,omitempty"`
+	Sources     map[string]string `json:"sources"`
+}
+
 func main() {
 	targetRoot := flag.String("target-root", "", "target repository root (defaults to cwd)")
 	tcFile := flag.String("tc-file", "docs/technology-constraints.md", "technology constraints file path")
+	adapterDirectives := flag.Bool("adapter-directives", false, "also discover adapters declared by adapter.yaml or `// adapter_id: <id>` directives in packages anywhere in the repo")
+	undeclaredMode := flag.String("undeclared", "warn", "policy for implemented adapters without a TC entry: warn|block")
+	allowlistFile := flag.String("undeclared-allowlist", "docs/tooling/adapter-allowlist.txt", "allowlist of adapters exempt from the undeclared policy (missing default file is ignored)")
 	flag.Parse()
 
 	root := strings.TrimSpace(*targetRoot)
 	if root == "" {
 		cwd, err := os.Getwd()
 		if err != nil {
-			printBlocked([]string{"unable to determine working directory"}, nil, nil)
+			printBlocked([]string{"unable to determine working directory"}, nil)
 			return
 		}
 		root = cwd
 	}
 	absRoot, err := filepath.Abs(root)
 	if err != nil {
-		printBlocked([]string{"invalid target root"}, nil, nil)
+		printBlocked([]string{"invalid target root"}, nil)
 		return
 	}
 
 	absTC := strings.TrimSpace(*tcFile)
 	if absTC == "" {
-		printBlocked([]string{"--tc-file cannot be empty"}, nil, nil)
+		printBlocked([]string{"--tc-file cannot be empty"}, nil)
 		return
 	}
 	if !filepath.IsAbs(absTC) {
 		absTC = filepath.Join(absRoot, filepath.FromSlash(absTC))
 	}
 
-	expected, err := parseExpectedAdapters(absTC)
+	mode := strings.ToLower(strings.TrimSpace(*undeclaredMode))
+	if mode != "warn" && mode != "block" {
+		printBlocked([]string{"--undeclared must be warn or block"}, nil)
+		return
+	}
+
+	allowlist := []declaredAdapter{}
+	allowlistPath := strings.TrimSpace(*allowlistFile)
+	if allowlistPath != "" {
+		if !filepath.IsAbs(allowlistPath) {
+			allowlistPath = filepath.Join(absRoot, filepath.FromSlash(allowlistPath))
+		}
+		entries, allowErr := parseAllowlist(allowlistPath)
+		switch {
+		case allowErr == nil:
+			allowlist = entries
+		case os.IsNotExist(allowErr) && !flagSet("undeclared-allowlist"):
+			allowlistPath = ""
+		default:
+			printBlocked([]string{fmt.Sprintf("failed to read undeclared allowlist: %v", allowErr)}, nil)
+			return
+		}
+	}
+
+	declared, err := parseExpectedAdapters(absTC)
 	if err != nil {
-		printBlocked([]string{fmt.Sprintf("failed to parse expected adapters: %v", err)}, nil, nil)
+		printBlocked([]string{fmt.Sprintf("failed to parse expected adapters: %v", err)}, nil)
 		return
 	}
-	if len(expected) == 0 {
-		printBlocked([]string{"no adapter_id/adaptor_id entries found in technology constraints"}, nil, nil)
+	if len(declared) == 0 {
+		printBlocked([]string{"no adapter_id/adaptor_id entries found in technology constraints"}, nil)
 		return
 	}
 
-	implemented := discoverImplementedAdapters(absRoot)
+	implementedByRepo := discoverImplementedAdapters(absRoot, *adapterDirectives)
+	matrix, unscopedMissing := buildParityMatrix(declared, implementedByRepo)
+	applyAllowlist(matrix, allowlist)
 
-	missing := diff(expected, implemented)
-	undeclared := diff(implemented, expected)
+	issues := []string{}
+	missing := append([]string{}, unscopedMissing...)
+	undeclared := []string{}
+	suggestions := []string{}
+	for _, row := range matrix {
+		if _, ok := implementedByRepo[row.Repo]; !ok {
+			issues = append(issues, fmt.Sprintf("declared repo not found: %s", row.Repo))
+		}
+		for _, id := range row.Missing {
+			missing = append(missing, row.Repo+":"+id)
+		}
+		for _, id := range row.Undeclared {
+			undeclared = append(undeclared, row.Repo+":"+id)
+			suggestions = append(suggestions, suggestedTCLine(id, row.Repo))
+		}
+	}
 
+	details := map[string]any{
+		"expected_adapters":    declaredIDs(declared),
+		"implemented_adapters": implementedIDs(implementedByRepo),
+		"missing_adapters":     missing,
+		"undeclared_adapters":  undeclared,
+		"undeclared_policy":    mode,
+		"parity_matrix":        matrix,
+	}
+	if allowlistPath != "" {
+		details["undeclared_allowlist"] = filepath.ToSlash(allowlistPath)
+	}
 	if len(missing) > 0 {
-		issues := []string{"declared adapters missing implementation"}
-		printBlocked(issues, missing, undeclared)
+		issues = append(issues, "declared adapters missing implementation")
+	}
+	if len(undeclared) > 0 {
+		details["tc_file"] = filepath.ToSlash(absTC)
+		details["suggested_tc_lines"] = suggestions
+		if mode == "block" {
+			issues = append(issues, fmt.Sprintf("implemented adapters without a TC entry; add the suggested adapter_id lines to %s or allowlist them", filepath.ToSlash(absTC)))
+		} else {
+			details["warnings"] = []string{"implemented adapters without a TC entry"}
+		}
+	}
+	if len(issues) > 0 {
+		printBlocked(issues, details)
 		return
 	}
 
-	printPass(expected, implemented, undeclared)
+	printPass(details)
 }
 
-func parseExpectedAdapters(path string) ([]string, error) {
+// parseExpectedAdapters reads adapter_id/adaptor_id lines. A `repo: <path>`
+// token on the same line scopes the entry to one repository; unscoped entries
+// are satisfied by an implementation in any repository.
+func parseExpectedAdapters(path string) ([]declaredAdapter, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
 	defer f.Close()
 
-	seen := map[string]bool{}
-	out := []string{}
+	seen := map[declaredAdapter]bool{}
+	out := []declaredAdapter{}
 	scanner := bufio.NewScanner(f)
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -83,63 +172,352 @@ func parseExpectedAdapters(path string) ([]string, error) {
 		if len(match) < 3 {
 			continue
 		}
-		id := strings.ToLower(strings.TrimSpace(match[2]))
-		if id == "" || seen[id] {
+		entry := declaredAdapter{ID: strings.ToLower(strings.TrimSpace(match[2]))}
+		if repoMatch := adapterRepoPattern.FindStringSubmatch(line); len(repoMatch) == 2 {
+			entry.Repo = normalizeRepo(repoMatch[1])
+		}
+		if entry.ID == "" || seen[entry] {
 			continue
 		}
-		seen[id] = true
-		out = append(out, id)
+		seen[entry] = true
+		out = append(out, entry)
 	}
 	if err := scanner.Err(); err != nil {
 		return nil, err
 	}
-	sort.Strings(out)
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].ID != out[j].ID {
+			return out[i].ID < out[j].ID
+		}
+		return out[i].Repo < out[j].Repo
+	})
+	return out, nil
+}
+
+// parseAllowlist reads one adapter per line, either as a bare ID or in the TC
+// `adapter_id: <id>` form, optionally scoped with `repo: <path>`. Lines
+// starting with # are comments.
+func parseAllowlist(path string) ([]declaredAdapter, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	out := []declaredAdapter{}
+	for _, raw := range strings.Split(string(data), "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entry := declaredAdapter{}
+		if match := adapterLinePattern.FindStringSubmatch(line); len(match) == 3 {
+			entry.ID = strings.ToLower(match[2])
+		} else {
+			entry.ID = strings.ToLower(strings.Fields(line)[0])
+		}
+		if repoMatch := adapterRepoPattern.FindStringSubmatch(line); len(repoMatch) == 2 {
+			entry.Repo = normalizeRepo(repoMatch[1])
+		}
+		out = append(out, entry)
+	}
 	return out, nil
 }
 
-func discoverImplementedAdapters(root string) []string {
-	candidateRoots := []string{
-		filepath.Join(root, "adapters"),
-		filepath.Join(root, "src", "adapters"),
-		filepath.Join(root, "internal", "adapters"),
-		filepath.Join(root, "pkg", "adapters"),
+func applyAllowlist(matrix []repoParity, allowlist []declaredAdapter) {
+	for i := range matrix {
+		kept := []string{}
+		for _, id := range matrix[i].Undeclared {
+			allowed := false
+			for _, entry := range allowlist {
+				if entry.ID == id && (entry.Repo == "" || entry.Repo == matrix[i].Repo) {
+					allowed = true
+					break
+				}
+			}
+			if allowed {
+				matrix[i].Allowlisted = append(matrix[i].Allowlisted, id)
+				continue
+			}
+			kept = append(kept, id)
+		}
+		matrix[i].Undeclared = kept
+	}
+}
+
+func suggestedTCLine(id, repo string) string {
+	if repo == "." {
+		return "adapter_id: " + id
+	}
+	return fmt.Sprintf("adapter_id: %s repo: %s", id, repo)
+}
+
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+func normalizeRepo(value string) string {
+	value = strings.Trim(strings.TrimPrefix(filepath.ToSlash(strings.TrimSpace(value)), "./"), "/")
+	if value == "" || value == "." {
+		return "."
+	}
+	if !strings.HasPrefix(value, "repos/") {
+		value = "repos/" + value
+	}
+	return value
+}
+
+func buildParityMatrix(declared []declaredAdapter, implementedByRepo map[string]map[string]string) ([]repoParity, []string) {
+	unscoped := map[string]bool{}
+	declaredByRepo := map[string][]string{}
+	for _, entry := range declared {
+		if entry.Repo == "" {
+			unscoped[entry.ID] = true
+			continue
+		}
+		declaredByRepo[entry.Repo] = append(declaredByRepo[entry.Repo], entry.ID)
+	}
+
+	repos := map[string]bool{}
+	for repo, sources := range implementedByRepo {
+		if repo != "." || len(sources) > 0 {
+			repos[repo] = true
+		}
+	}
+	for repo := range declaredByRepo {
+		repos[repo] = true
+	}
+
+	matrix := []repoParity{}
+	for _, repo := range sortedSet(repos) {
+		sources := implementedByRepo[repo]
+		if sources == nil {
+			sources = map[string]string{}
+		}
+		implemented := sortedKeys(sources)
+		repoDeclared := declaredByRepo[repo]
+		if repoDeclared == nil {
+			repoDeclared = []string{}
+		}
+		undeclared := []string{}
+		for _, id := range diff(implemented, repoDeclared) {
+			if !unscoped[id] {
+				undeclared = append(undeclared, id)
+			}
+		}
+		matrix = append(matrix, repoParity{
+			Repo:        repo,
+			Declared:    repoDeclared,
+			Implemented: implemented,
+			Missing:     diff(repoDeclared, implemented),
+			Undeclared:  undeclared,
+			Sources:     sources,
+		})
+	}
+
+	unscopedMissing := []string{}
+	for _, id := range sortedSet(unscoped) {
+		found := false
+		for _, sources := range implementedByRepo {
+			if sources[id] != "" {
+				found = true
+				break
+			}
+		}
+		if !found {
+			unscopedMissing = append(unscopedMissing, id)
+		}
+	}
+	return matrix, unscopedMissing
+}
+
+func declaredIDs(declared []declaredAdapter) []string {
+	set := map[string]bool{}
+	for _, entry := range declared {
+		set[entry.ID] = true
+	}
+	return sortedSet(set)
+}
+
+func implementedIDs(implementedByRepo map[string]map[string]string) []string {
+	set := map[string]bool{}
+	for _, sources := range implementedByRepo {
+		for id := range sources {
+			set[id] = true
+		}
 	}
+	return sortedSet(set)
+}
 
+// discoverImplementedAdapters maps each repo (the target root as "." and every
+// repos/* entry) to its implemented adapter IDs and the file providing each.
+func discoverImplementedAdapters(root string, directives bool) map[string]map[string]string {
+	repoRoots := map[string]string{".": root}
 	reposRoot := filepath.Join(root, "repos")
 	if repoEntries, repoErr := os.ReadDir(reposRoot); repoErr == nil {
 		for _, repoEntry := range repoEntries {
 			if !repoEntry.IsDir() {
 				continue
 			}
-			repoRoot := filepath.Join(reposRoot, repoEntry.Name())
-			candidateRoots = append(candidateRoots,
-				filepath.Join(repoRoot, "adapters"),
-				filepath.Join(repoRoot, "src", "adapters"),
-				filepath.Join(repoRoot, "internal", "adapters"),
-				filepath.Join(repoRoot, "pkg", "adapters"),
-			)
+			repoRoots["repos/"+repoEntry.Name()] = filepath.Join(reposRoot, repoEntry.Name())
 		}
 	}
 
-	seen := map[string]bool{}
-	out := []string{}
-	for _, candidate := range candidateRoots {
-		entries, err := os.ReadDir(candidate)
-		if err != nil {
-			continue
+	out := map[string]map[string]string{}
+	for repo, repoRoot := range repoRoots {
+		sources := map[string]string{}
+		record := func(id, file string) {
+			id = strings.ToLower(strings.TrimSpace(id))
+			if id == "" || sources[id] != "" {
+				return
+			}
+			rel, err := filepath.Rel(root, file)
+			if err != nil {
+				rel = file
+			}
+			sources[id] = filepath.ToSlash(rel)
 		}
-		for _, entry := range entries {
-			if !entry.IsDir() {
+
+		candidateRoots := []string{
+			filepath.Join(repoRoot, "adapters"),
+			filepath.Join(repoRoot, "src", "adapters"),
+			filepath.Join(repoRoot, "internal", "adapters"),
+			filepath.Join(repoRoot, "pkg", "adapters"),
+		}
+		for _, candidate := range candidateRoots {
+			entries, err := os.ReadDir(candidate)
+			if err != nil {
 				continue
 			}
-			name := strings.ToLower(strings.TrimSpace(entry.Name()))
-			if name == "" || seen[name] {
+			for _, entry := range entries {
+				if !entry.IsDir() {
+					continue
+				}
+				if file := firstGoSource(filepath.Join(candidate, entry.Name()), true); file != "" {
+					record(entry.Name(), file)
+				}
+			}
+		}
+		if directives {
+			directiveSources := discoverAdapterDirectives(repoRoot, repo == ".")
+			for _, id := range sortedKeys(directiveSources) {
+				record(id, directiveSources[id])
+			}
+		}
+		out[repo] = sources
+	}
+	return out
+}
+
+// discoverAdapterDirectives walks a repo for packages that declare their adapter
+// through an adapter.yaml file or a `// adapter_id: <id>` comment ahead of the
+// package clause. Nested repos are skipped when walking the workspace root.
+func discoverAdapterDirectives(repoRoot string, skipRepos bool) map[string]string {
+	found := map[string]string{}
+	_ = filepath.WalkDir(repoRoot, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != repoRoot && (strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules" || name == "testdata") {
+				return filepath.SkipDir
+			}
+			if skipRepos && path == filepath.Join(repoRoot, "repos") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		name := strings.ToLower(d.Name())
+		switch {
+		case name == "adapter.yaml" || name == "adapter.yml":
+			if firstGoSource(filepath.Dir(path), false) == "" {
+				return nil
+			}
+			entries, readErr := parseExpectedAdapters(path)
+			if readErr != nil {
+				return nil
+			}
+			for _, entry := range entries {
+				if _, ok := found[entry.ID]; !ok {
+					found[entry.ID] = path
+				}
+			}
+		case isGoSource(name):
+			for _, id := range goAdapterDirectives(path) {
+				if _, ok := found[id]; !ok {
+					found[id] = path
+				}
+			}
+		}
+		return nil
+	})
+	return found
+}
+
+func goAdapterDirectives(path string) []string {
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.PackageClauseOnly|parser.ParseComments)
+	if err != nil {
+		return nil
+	}
+	out := []string{}
+	for _, group := range file.Comments {
+		for _, line := range strings.Split(group.Text(), "\n") {
+			match := adapterDirectivePattern.FindStringSubmatch(strings.TrimSpace(line))
+			if len(match) < 3 {
 				continue
 			}
-			seen[name] = true
-			out = append(out, name)
+			out = append(out, strings.ToLower(strings.TrimSpace(match[2])))
 		}
 	}
+	return out
+}
+
+func isGoSource(name string) bool {
+	return strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go")
+}
+
+// firstGoSource returns the first non-test Go file in dir (searching
+// subdirectories when recursive is set), or "" when there is none.
+func firstGoSource(dir string, recursive bool) string {
+	found := ""
+	_ = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != dir && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if isGoSource(strings.ToLower(d.Name())) {
+			found = path
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found
+}
+
+func sortedKeys(m map[string]string) []string {
+	out := make([]string, 0, len(m))
+	for key := range m {
+		out = append(out, key)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func sortedSet(m map[string]bool) []string {
+	out := make([]string, 0, len(m))
+	for key := range m {
+		out = append(out, key)
+	}
 	sort.Strings(out)
 	return out
 }
@@ -158,22 +536,25 @@ func diff(a, b []string) []string {
 	return out
 }
 
-func printBlocked(issues, missing, undeclared []string) {
-	payload, _ := json.Marshal(map[string]any{
-		"status":              "BLOCKED",
-		"issues":              issues,
-		"missing_adapters":    missing,
-		"undeclared_adapters": undeclared,
-	})
-	fmt.Println(string(payload))
+func printBlocked(issues []string, details map[string]any) {
+	payload := map[string]any{
+		"status": "BLOCKED",
+		"issues": issues,
+	}
+	for k, v := range details {
+		payload[k] = v
+	}
+	data, _ := json.Marshal(payload)
+	fmt.Println(string(data))
 }
 
-func printPass(expected, implemented, undeclared []string) {
-	payload, _ := json.Marshal(map[string]any{
-		"status":               "PASS",
-		"expected_adapters":    expected,
-		"implemented_adapters": implemented,
-		"undeclared_adapters":  undeclared,
-	})
-	fmt.Println(string(payload))
+func printPass(details map[string]any) {
+	payload := map[string]any{
+		"status": "PASS",
+	}
+	for k, v := range details {
+		payload[k] = v
+	}
+	data, _ := json.Marshal(payload)
+	fmt.Println(string(data))
 }
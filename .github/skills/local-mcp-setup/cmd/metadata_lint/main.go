@@ -0,0 +1,333 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// metadataRules lists the governance front matter contract. Keys present in
+// a rules file replace the matching built-in value.
+type metadataRules struct {
+	RequiredKeys   []string            `json:"required_keys"`
+	Enums          map[string][]string `json:"enums"`
+	Patterns       map[string]string   `json:"patterns"`
+	GovernanceKeys []string            `json:"governance_keys"`
+}
+
+type frontMatter struct {
+	values map[string]string
+	lines  map[string]int
+}
+
+type metadataDoc struct {
+	path string
+	fm   frontMatter
+}
+
+type metadataFinding struct {
+	Rule    string `json:"rule"`
+	Path    string `json:"path"`
+	Line    int    `json:"line,omitempty"`
+	Key     string `json:"key,omitempty"`
+	Message string `json:"message"`
+}
+
+func defaultRules() metadataRules {
+	return metadataRules{
+		RequiredKeys: []string{"doc_id", "doc_type", "concern", "status", "owner_role", "accountable_role", "source_of_truth", "version"},
+		Enums: map[string][]string{
+			"doc_type":        {"decision", "architecture", "policy", "standard", "guidance", "requirements", "plan", "runbook", "reference"},
+			"concern":         {"architecture", "integration", "security", "data", "operations", "delivery", "governance", "product", "quality", "compliance"},
+			"status":          {"draft", "proposed", "accepted", "approved", "superseded", "deprecated", "rejected"},
+			"source_of_truth": {"true", "false"},
+		},
+		Patterns: map[string]string{
+			"doc_id":           `^[A-Z][A-Z0-9]*(-[A-Z0-9]+)+$`,
+			"owner_role":       `^[a-z][a-z0-9_]*$`,
+			"accountable_role": `^[a-z][a-z0-9_]*$`,
+			"version":          `^\d+(\.\d+){0,2}$`,
+		},
+		GovernanceKeys: []string{"doc_id", "concern", "owner_role", "accountable_role", "source_of_truth"},
+	}
+}
+
+func main() {
+	targetRoot := flag.String("target-root", "", "target repository root (defaults to cwd)")
+	scanDirs := flag.String("scan-dirs", "docs,.github/skills/local-mcp-setup/corporate-docs", "comma-separated directories (relative to target root) to scan for governance documents")
+	rulesFile := flag.String("rules-file", "docs/tooling/metadata-rules.json", "JSON metadata rules; a missing default file uses built-in rules")
+	flag.Parse()
+
+	root := strings.TrimSpace(*targetRoot)
+	if root == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			printBlocked([]string{"unable to determine working directory"}, nil)
+			return
+		}
+		root = cwd
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		printBlocked([]string{"invalid target root"}, nil)
+		return
+	}
+
+	rules, rulesPath, err := loadRules(absRoot, strings.TrimSpace(*rulesFile), flagSet("rules-file"))
+	if err != nil {
+		printBlocked([]string{fmt.Sprintf("invalid metadata rules: %v", err)}, nil)
+		return
+	}
+	patterns := map[string]*regexp.Regexp{}
+	for key, pattern := range rules.Patterns {
+		compiled, compileErr := regexp.Compile(pattern)
+		if compileErr != nil {
+			printBlocked([]string{fmt.Sprintf("invalid metadata rules: pattern for %s: %v", key, compileErr)}, nil)
+			return
+		}
+		patterns[key] = compiled
+	}
+
+	scanned := []string{}
+	docs := []metadataDoc{}
+	for _, dir := range strings.Split(*scanDirs, ",") {
+		dir = strings.TrimSpace(dir)
+		if dir == "" {
+			continue
+		}
+		base := filepath.Join(absRoot, filepath.FromSlash(dir))
+		if info, statErr := os.Stat(base); statErr != nil || !info.IsDir() {
+			continue
+		}
+		scanned = append(scanned, filepath.ToSlash(dir))
+		_ = filepath.WalkDir(base, func(path string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !isMarkdown(path) {
+				return nil
+			}
+			data, readErr := os.ReadFile(path)
+			if readErr != nil {
+				return nil
+			}
+			fm, ok := parseFrontMatter(string(data))
+			if !ok || !isGovernanceDoc(fm, rules.GovernanceKeys) {
+				return nil
+			}
+			rel, relErr := filepath.Rel(absRoot, path)
+			if relErr != nil {
+				rel = path
+			}
+			docs = append(docs, metadataDoc{path: filepath.ToSlash(rel), fm: fm})
+			return nil
+		})
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].path < docs[j].path })
+
+	findings := []metadataFinding{}
+	for _, doc := range docs {
+		findings = append(findings, checkDoc(doc, rules, patterns)...)
+	}
+	findings = append(findings, checkUniqueness(docs)...)
+
+	details := map[string]any{
+		"scan_dirs":         scanned,
+		"rules_file":        filepath.ToSlash(rulesPath),
+		"documents_checked": len(docs),
+		"required_keys":     rules.RequiredKeys,
+	}
+	if len(findings) > 0 {
+		details["findings"] = findings
+		printBlocked([]string{"governance document metadata findings"}, details)
+		return
+	}
+
+	details["status"] = "PASS"
+	payload, _ := json.Marshal(details)
+	fmt.Println(string(payload))
+}
+
+func loadRules(root, value string, explicit bool) (metadataRules, string, error) {
+	rules := defaultRules()
+	if value == "" {
+		return rules, "", nil
+	}
+	path := value
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, filepath.FromSlash(path))
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if explicit {
+			return rules, path, err
+		}
+		return rules, "", nil
+	}
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return rules, path, err
+	}
+	return rules, path, nil
+}
+
+// parseFrontMatter reads flat `key: value` pairs from a leading `---` block,
+// recording the line of each key. Quotes around values are removed.
+func parseFrontMatter(content string) (frontMatter, bool) {
+	fm := frontMatter{values: map[string]string{}, lines: map[string]int{}}
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return fm, false
+	}
+	for i := 1; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "---" {
+			return fm, true
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.HasPrefix(line, " ") || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '\'' || value[0] == '"') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		if _, seen := fm.values[key]; !seen {
+			fm.lines[key] = i + 1
+		}
+		fm.values[key] = value
+	}
+	return fm, false
+}
+
+// isGovernanceDoc limits the lint to documents that opt into the governance
+// contract; plain notes with only a title or doc_type are not checked.
+func isGovernanceDoc(fm frontMatter, keys []string) bool {
+	for _, key := range keys {
+		if _, ok := fm.values[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func checkDoc(doc metadataDoc, rules metadataRules, patterns map[string]*regexp.Regexp) []metadataFinding {
+	findings := []metadataFinding{}
+	for _, key := range rules.RequiredKeys {
+		if strings.TrimSpace(doc.fm.values[key]) == "" {
+			findings = append(findings, metadataFinding{Rule: "required-key", Path: doc.path, Key: key, Message: fmt.Sprintf("missing required front matter key %q", key)})
+		}
+	}
+
+	enumKeys := make([]string, 0, len(rules.Enums))
+	for key := range rules.Enums {
+		enumKeys = append(enumKeys, key)
+	}
+	sort.Strings(enumKeys)
+	for _, key := range enumKeys {
+		value := strings.TrimSpace(doc.fm.values[key])
+		if value == "" {
+			continue
+		}
+		allowed := false
+		for _, candidate := range rules.Enums[key] {
+			if strings.EqualFold(candidate, value) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			findings = append(findings, metadataFinding{Rule: "enum", Path: doc.path, Line: doc.fm.lines[key], Key: key, Message: fmt.Sprintf("%s %q is not one of: %s", key, value, strings.Join(rules.Enums[key], ", "))})
+		}
+	}
+
+	patternKeys := make([]string, 0, len(patterns))
+	for key := range patterns {
+		patternKeys = append(patternKeys, key)
+	}
+	sort.Strings(patternKeys)
+	for _, key := range patternKeys {
+		value := strings.TrimSpace(doc.fm.values[key])
+		if value != "" && !patterns[key].MatchString(value) {
+			findings = append(findings, metadataFinding{Rule: "pattern", Path: doc.path, Line: doc.fm.lines[key], Key: key, Message: fmt.Sprintf("%s %q does not match %s", key, value, rules.Patterns[key])})
+		}
+	}
+	return findings
+}
+
+// checkUniqueness reports doc_ids used by more than one document and concerns
+// with more than one source_of_truth: true document.
+func checkUniqueness(docs []metadataDoc) []metadataFinding {
+	byID := map[string][]metadataDoc{}
+	bySourceConcern := map[string][]metadataDoc{}
+	for _, doc := range docs {
+		if id := strings.TrimSpace(doc.fm.values["doc_id"]); id != "" {
+			byID[strings.ToUpper(id)] = append(byID[strings.ToUpper(id)], doc)
+		}
+		concern := strings.ToLower(strings.TrimSpace(doc.fm.values["concern"]))
+		if concern != "" && strings.EqualFold(strings.TrimSpace(doc.fm.values["source_of_truth"]), "true") {
+			bySourceConcern[concern] = append(bySourceConcern[concern], doc)
+		}
+	}
+
+	findings := []metadataFinding{}
+	ids := make([]string, 0, len(byID))
+	for id := range byID {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		group := byID[id]
+		if len(group) < 2 {
+			continue
+		}
+		for _, doc := range group[1:] {
+			findings = append(findings, metadataFinding{Rule: "duplicate-doc-id", Path: doc.path, Line: doc.fm.lines["doc_id"], Key: "doc_id", Message: fmt.Sprintf("doc_id %s is already used by %s", id, group[0].path)})
+		}
+	}
+
+	concerns := make([]string, 0, len(bySourceConcern))
+	for concern := range bySourceConcern {
+		concerns = append(concerns, concern)
+	}
+	sort.Strings(concerns)
+	for _, concern := range concerns {
+		group := bySourceConcern[concern]
+		if len(group) < 2 {
+			continue
+		}
+		for _, doc := range group[1:] {
+			findings = append(findings, metadataFinding{Rule: "duplicate-source-of-truth", Path: doc.path, Line: doc.fm.lines["source_of_truth"], Key: "source_of_truth", Message: fmt.Sprintf("concern %q already has a source of truth: %s", concern, group[0].path)})
+		}
+	}
+	return findings
+}
+
+func isMarkdown(path string) bool {
+	name := strings.ToLower(path)
+	return strings.HasSuffix(name, ".md") || strings.HasSuffix(name, ".markdown")
+}
+
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+func printBlocked(issues []string, details map[string]any) {
+	payload := map[string]any{
+		"status": "BLOCKED",
+		"issues": issues,
+	}
+	for k, v := range details {
+		payload[k] = v
+	}
+	data, _ := json.Marshal(payload)
+	fmt.Println(string(data))
+}
@@ -0,0 +1,876 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// contractChange is one difference between the base and head contracts.
+type contractChange struct {
+	Kind     string `json:"kind"`
+	Location string `json:"location"`
+	Message  string `json:"message"`
+}
+
+func main() {
+	targetRoot := flag.String("target-root", "", "target repository root (defaults to cwd)")
+	headFile := flag.String("head", "repos/openapi-contracts/openapi/openapi.yaml", "new OpenAPI document (working tree)")
+	baseFile := flag.String("base", "", "previous OpenAPI document to compare against")
+	baseRef := flag.String("base-ref", "", "git ref holding the previous version of --head (instead of --base)")
+	profileFile := flag.String("profile-file", "", "optional explicit planning behavior profile path")
+	openapiLint := flag.String("openapi-lint", "", "openapi_lint command source used to decode YAML contracts (defaults to .github/skills/local-mcp-setup/cmd/openapi_lint/main.go under the working directory, then the target root)")
+	flag.Parse()
+
+	root := strings.TrimSpace(*targetRoot)
+	if root == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			printBlocked([]string{"unable to determine working directory"}, nil)
+			return
+		}
+		root = cwd
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		printBlocked([]string{"invalid target root"}, nil)
+		return
+	}
+
+	if (strings.TrimSpace(*baseFile) == "") == (strings.TrimSpace(*baseRef) == "") {
+		printBlocked([]string{"set exactly one of --base or --base-ref"}, nil)
+		return
+	}
+	decoder := &yamlDecoder{command: openapiLintCommand(absRoot, strings.TrimSpace(*openapiLint))}
+	defer decoder.close()
+	headPath := resolvePath(absRoot, *headFile)
+	head := newContractSource(headPath, "", decoder)
+	base := newContractSource(resolvePath(absRoot, *baseFile), "", decoder)
+	if ref := strings.TrimSpace(*baseRef); ref != "" {
+		base = newContractSource(headPath, ref, decoder)
+	}
+
+	required, profilePath, err := versioningPolicyRequired(absRoot, strings.TrimSpace(*profileFile))
+	if err != nil {
+		printBlocked([]string{fmt.Sprintf("failed to read planning behavior profile: %v", err)}, nil)
+		return
+	}
+
+	baseDoc, err := base.root()
+	if err != nil {
+		printBlocked([]string{fmt.Sprintf("cannot read base contract %s: %v", base.label(), err)}, nil)
+		return
+	}
+	headDoc, err := head.root()
+	if err != nil {
+		printBlocked([]string{fmt.Sprintf("cannot read head contract %s: %v", head.label(), err)}, nil)
+		return
+	}
+
+	d := &contractDiff{seen: map[string]bool{}, breaking: []contractChange{}, nonBreaking: []contractChange{}}
+	d.comparePaths(baseDoc, headDoc)
+
+	baseVersion := scalarString(baseDoc.get("info").get("version").value)
+	headVersion := scalarString(headDoc.get("info").get("version").value)
+	requiredBump := requiredBump(baseVersion, len(d.breaking) > 0, len(d.nonBreaking) > 0)
+	actualBump, bumpErr := versionBump(baseVersion, headVersion)
+
+	details := map[string]any{
+		"base":                       base.label(),
+		"head":                       head.label(),
+		"base_version":               baseVersion,
+		"head_version":               headVersion,
+		"breaking":                   d.breaking,
+		"non_breaking":               d.nonBreaking,
+		"required_bump":              requiredBump,
+		"actual_bump":                actualBump,
+		"versioning_policy_required": required,
+		"profile_file":               filepath.ToSlash(profilePath),
+	}
+
+	issues := []string{}
+	switch {
+	case bumpErr != nil:
+		issues = append(issues, bumpErr.Error())
+	case actualBump == "downgrade":
+		issues = append(issues, fmt.Sprintf("info.version went backwards: %s -> %s", baseVersion, headVersion))
+	case bumpRank[actualBump] < bumpRank[requiredBump]:
+		issues = append(issues, fmt.Sprintf("info.version %s -> %s is a %s bump; %d breaking and %d non-breaking change(s) require a %s bump", baseVersion, headVersion, actualBump, len(d.breaking), len(d.nonBreaking), requiredBump))
+	}
+	if len(issues) > 0 && required {
+		printBlocked(issues, details)
+		return
+	}
+	if len(issues) > 0 {
+		details["warnings"] = issues
+	}
+
+	details["status"] = "PASS"
+	payload, _ := json.Marshal(details)
+	fmt.Println(string(payload))
+}
+
+var bumpRank = map[string]int{"none": 0, "patch": 1, "minor": 2, "major": 3}
+
+var semverPattern = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)(?:[-+].*)?$`)
+
+// requiredBump applies the semver policy: breaking changes need a major bump
+// (minor while the major version is 0), other changes a minor bump (patch
+// while 0.x). Documents without operation-level changes need no bump.
+func requiredBump(baseVersion string, breaking, nonBreaking bool) string {
+	initial := strings.HasPrefix(strings.TrimPrefix(baseVersion, "v"), "0.")
+	switch {
+	case breaking && initial:
+		return "minor"
+	case breaking:
+		return "major"
+	case nonBreaking && initial:
+		return "patch"
+	case nonBreaking:
+		return "minor"
+	}
+	return "none"
+}
+
+func versionBump(base, head string) (string, error) {
+	b := semverPattern.FindStringSubmatch(base)
+	h := semverPattern.FindStringSubmatch(head)
+	if b == nil || h == nil {
+		return "", fmt.Errorf("info.version must be semver MAJOR.MINOR.PATCH (base %q, head %q)", base, head)
+	}
+	for i, kind := range []string{"major", "minor", "patch"} {
+		bv, _ := strconv.Atoi(b[i+1])
+		hv, _ := strconv.Atoi(h[i+1])
+		if hv > bv {
+			return kind, nil
+		}
+		if hv < bv {
+			return "downgrade", nil
+		}
+	}
+	return "none", nil
+}
+
+// versioningPolicyRequired reads contracts.versioning_policy_required from the
+// resolved planning behavior profile; without a profile the policy applies.
+func versioningPolicyRequired(targetRoot, explicit string) (bool, string, error) {
+	profilePath := resolveProfile(targetRoot, explicit)
+	if profilePath == "" {
+		if explicit != "" {
+			return true, "", fmt.Errorf("profile not found: %s", explicit)
+		}
+		return true, "", nil
+	}
+	values, err := readTopLevelScalars(profilePath)
+	if err != nil {
+		return true, profilePath, err
+	}
+	if value, ok := values["contracts.versioning_policy_required"]; ok {
+		return value == "true", profilePath, nil
+	}
+	return true, profilePath, nil
+}
+
+// readTopLevelScalars flattens the profile's nested scalar keys into dotted
+// paths (contracts.versioning_policy_required); list entries are skipped.
+func readTopLevelScalars(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	out := map[string]string{}
+	parents := []string{}
+	indents := []int{}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "-") {
+			continue
+		}
+
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		for len(indents) > 0 && indent <= indents[len(indents)-1] {
+			indents = indents[:len(indents)-1]
+			parents = parents[:len(parents)-1]
+		}
+
+		idx := strings.Index(trimmed, ":")
+		if idx <= 0 {
+			continue
+		}
+		key := strings.TrimSpace(trimmed[:idx])
+		value := strings.TrimSpace(trimmed[idx+1:])
+		if hash := strings.Index(value, " #"); hash >= 0 {
+			value = strings.TrimSpace(value[:hash])
+		}
+
+		fullPath := strings.Join(append(append([]string{}, parents...), key), ".")
+		if value == "" {
+			parents = append(parents, key)
+			indents = append(indents, indent)
+			continue
+		}
+		out[fullPath] = strings.Trim(value, "\"'")
+	}
+	return out, scanner.Err()
+}
+
+func resolveProfile(targetRoot, explicit string) string {
+	if explicit != "" {
+		if exists(explicit) {
+			return explicit
+		}
+		joined := filepath.Join(targetRoot, explicit)
+		if exists(joined) {
+			return joined
+		}
+		return ""
+	}
+
+	candidates := []string{
+		filepath.Join(targetRoot, "docs", "source", "02-architecture", "planning-behavior-profile.yaml"),
+		filepath.Join(targetRoot, "docs", "source", "DemoArchitectureDocs", "planning-behavior-profile.yaml"),
+		filepath.Join(targetRoot, ".github", "skills", "local-mcp-setup", "corporate-docs", "planning-behavior-profile.yaml"),
+	}
+	for _, candidate := range candidates {
+		if exists(candidate) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// contractSource loads a contract and the files its $refs point to, either
+// from disk or, with a git ref, from that commit.
+type contractSource struct {
+	dir     string
+	name    string
+	ref     string
+	files   map[string]any
+	decoder *yamlDecoder
+}
+
+func newContractSource(file, ref string, decoder *yamlDecoder) *contractSource {
+	return &contractSource{dir: filepath.Dir(file), name: filepath.Base(file), ref: ref, files: map[string]any{}, decoder: decoder}
+}
+
+func (s *contractSource) label() string {
+	file := filepath.ToSlash(filepath.Join(s.dir, s.name))
+	if s.ref != "" {
+		return s.ref + ":" + file
+	}
+	return file
+}
+
+func (s *contractSource) root() (contractNode, error) {
+	doc, err := s.load(s.name)
+	if err != nil {
+		return contractNode{}, err
+	}
+	if _, ok := doc.(map[string]any); !ok {
+		return contractNode{}, fmt.Errorf("document root must be a mapping")
+	}
+	return contractNode{src: s, file: s.name, value: doc}, nil
+}
+
+// load reads a file given relative to the root document's directory.
+func (s *contractSource) load(rel string) (any, error) {
+	rel = path.Clean(rel)
+	if doc, ok := s.files[rel]; ok {
+		return doc, nil
+	}
+	var data []byte
+	var err error
+	if s.ref != "" {
+		cmd := exec.Command("git", "-C", s.dir, "show", s.ref+":./"+rel)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		data, err = cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("git show %s:./%s: %s", s.ref, rel, strings.TrimSpace(stderr.String()))
+		}
+	} else {
+		data, err = os.ReadFile(filepath.Join(s.dir, filepath.FromSlash(rel)))
+		if err != nil {
+			return nil, err
+		}
+	}
+	doc, err := parseContractData(rel, data, s.decoder)
+	if err != nil {
+		return nil, err
+	}
+	s.files[rel] = doc
+	return doc, nil
+}
+
+// parseContractData decodes JSON (by extension or a leading brace) or YAML;
+// JSON numbers keep their literal text so both formats compare alike.
+func parseContractData(name string, data []byte, decoder *yamlDecoder) (any, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.EqualFold(path.Ext(name), ".json") || strings.HasPrefix(trimmed, "{") {
+		decoder := json.NewDecoder(strings.NewReader(trimmed))
+		decoder.UseNumber()
+		var doc any
+		if err := decoder.Decode(&doc); err != nil {
+			return nil, err
+		}
+		return normalizeJSONNumbers(doc), nil
+	}
+	return decoder.decode(name, data)
+}
+
+// openapiLintCommand locates the openapi_lint source. YAML contracts are
+// decoded by its --decode mode so the OpenAPI YAML reader lives in one place.
+func openapiLintCommand(targetRoot, explicit string) string {
+	if explicit != "" {
+		return resolvePath(targetRoot, explicit)
+	}
+	rel := filepath.Join(".github", "skills", "local-mcp-setup", "cmd", "openapi_lint", "main.go")
+	if cwd, err := os.Getwd(); err == nil && exists(filepath.Join(cwd, rel)) {
+		return filepath.Join(cwd, rel)
+	}
+	return filepath.Join(targetRoot, rel)
+}
+
+// yamlDecoder builds openapi_lint once per run and decodes each YAML file
+// through it.
+type yamlDecoder struct {
+	command string
+	dir     string
+	binary  string
+	err     error
+}
+
+func (y *yamlDecoder) decode(name string, data []byte) (any, error) {
+	if y.binary == "" && y.err == nil {
+		y.err = y.build()
+	}
+	if y.err != nil {
+		return nil, y.err
+	}
+	cmd := exec.Command(y.binary, "--decode", path.Base(name))
+	cmd.Stdin = bytes.NewReader(data)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("openapi_lint --decode: %v", err)
+	}
+	var result struct {
+		Status   string   `json:"status"`
+		Issues   []string `json:"issues"`
+		Document any      `json:"document"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("openapi_lint --decode: %v", err)
+	}
+	if result.Status != "PASS" {
+		return nil, fmt.Errorf("%s", strings.Join(result.Issues, "; "))
+	}
+	return result.Document, nil
+}
+
+func (y *yamlDecoder) build() error {
+	if !exists(y.command) {
+		return fmt.Errorf("openapi_lint command not found at %s (set --openapi-lint)", filepath.ToSlash(y.command))
+	}
+	dir, err := os.MkdirTemp("", "openapi-lint-")
+	if err != nil {
+		return err
+	}
+	y.dir = dir
+	binary := filepath.Join(dir, "openapi_lint")
+	cmd := exec.Command("go", "build", "-o", binary, y.command)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("go build %s: %s", filepath.ToSlash(y.command), strings.TrimSpace(stderr.String()))
+	}
+	y.binary = binary
+	return nil
+}
+
+func (y *yamlDecoder) close() {
+	if y.dir != "" {
+		_ = os.RemoveAll(y.dir)
+	}
+}
+
+func normalizeJSONNumbers(node any) any {
+	switch typed := node.(type) {
+	case map[string]any:
+		for key, value := range typed {
+			typed[key] = normalizeJSONNumbers(value)
+		}
+	case []any:
+		for i, value := range typed {
+			typed[i] = normalizeJSONNumbers(value)
+		}
+	case json.Number:
+		return typed.String()
+	}
+	return node
+}
+
+// contractNode is a value inside a loaded contract together with the file it
+// came from, so relative $refs resolve against the right document. ref names
+// the last $ref followed to reach it.
+type contractNode struct {
+	src   *contractSource
+	file  string
+	value any
+	ref   string
+}
+
+// resolve follows $ref chains; an unresolvable ref yields an empty node.
+func (n contractNode) resolve() contractNode {
+	for depth := 0; depth < 16; depth++ {
+		item, ok := n.value.(map[string]any)
+		if !ok {
+			return n
+		}
+		ref := scalarString(item["$ref"])
+		if ref == "" || n.src == nil {
+			return n
+		}
+		target, fragment, _ := strings.Cut(ref, "#")
+		file := n.file
+		if target != "" {
+			file = path.Join(path.Dir(n.file), target)
+		}
+		doc, err := n.src.load(file)
+		if err != nil {
+			return contractNode{}
+		}
+		node := doc
+		if fragment != "" && fragment != "/" {
+			for _, token := range strings.Split(strings.TrimPrefix(fragment, "/"), "/") {
+				token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+				switch typed := node.(type) {
+				case map[string]any:
+					node = typed[token]
+				case []any:
+					index, convErr := strconv.Atoi(token)
+					if convErr != nil || index < 0 || index >= len(typed) {
+						return contractNode{}
+					}
+					node = typed[index]
+				default:
+					return contractNode{}
+				}
+			}
+		}
+		n = contractNode{src: n.src, file: file, value: node, ref: file + "#" + fragment}
+	}
+	return n
+}
+
+func (n contractNode) get(key string) contractNode {
+	n = n.resolve()
+	item, _ := n.value.(map[string]any)
+	return contractNode{src: n.src, file: n.file, value: item[key]}
+}
+
+func (n contractNode) mapping() map[string]any {
+	item, _ := n.resolve().value.(map[string]any)
+	return item
+}
+
+func (n contractNode) present() bool {
+	return n.resolve().value != nil
+}
+
+type contractDiff struct {
+	breaking    []contractChange
+	nonBreaking []contractChange
+	seen        map[string]bool
+}
+
+func (d *contractDiff) add(breaking bool, kind, location, format string, args ...any) {
+	change := contractChange{Kind: kind, Location: location, Message: fmt.Sprintf(format, args...)}
+	if breaking {
+		d.breaking = append(d.breaking, change)
+	} else {
+		d.nonBreaking = append(d.nonBreaking, change)
+	}
+}
+
+var (
+	pathTemplate     = regexp.MustCompile(`\{[^}/]+\}`)
+	operationMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+)
+
+// comparePaths matches paths with template names ignored, so renaming
+// /orders/{id} to /orders/{orderId} is not a removal.
+func (d *contractDiff) comparePaths(base, head contractNode) {
+	basePaths := normalizedPaths(base.get("paths").mapping())
+	headPaths := normalizedPaths(head.get("paths").mapping())
+	for _, key := range unionKeys(basePaths, headPaths) {
+		baseRoute, inBase := basePaths[key].(string)
+		headRoute, inHead := headPaths[key].(string)
+		switch {
+		case !inHead:
+			d.add(true, "path-removed", baseRoute, "path %s was removed", baseRoute)
+			continue
+		case !inBase:
+			d.add(false, "path-added", headRoute, "path %s was added", headRoute)
+			continue
+		}
+		baseItem := base.get("paths").get(baseRoute)
+		headItem := head.get("paths").get(headRoute)
+		for _, method := range operationMethods {
+			location := strings.ToUpper(method) + " " + headRoute
+			baseOp, headOp := baseItem.get(method), headItem.get(method)
+			switch {
+			case !baseOp.present() && !headOp.present():
+			case !headOp.present():
+				d.add(true, "operation-removed", location, "operation was removed")
+			case !baseOp.present():
+				d.add(false, "operation-added", location, "operation was added")
+			default:
+				d.compareOperation(location, baseRoute, headRoute, baseItem, baseOp, headItem, headOp)
+			}
+		}
+	}
+}
+
+func normalizedPaths(paths map[string]any) map[string]any {
+	out := map[string]any{}
+	for route := range paths {
+		out[pathTemplate.ReplaceAllString(route, "{}")] = route
+	}
+	return out
+}
+
+func (d *contractDiff) compareOperation(location, baseRoute, headRoute string, baseItem, baseOp, headItem, headOp contractNode) {
+	baseParams := operationParameters(baseRoute, baseItem, baseOp)
+	headParams := operationParameters(headRoute, headItem, headOp)
+	for _, key := range unionKeys(baseParams, headParams) {
+		baseParam, inBase := baseParams[key].(contractNode)
+		headParam, inHead := headParams[key].(contractNode)
+		named := headParam
+		if !inHead {
+			named = baseParam
+		}
+		paramLocation := location + " parameter " + scalarString(named.get("in").value) + ":" + scalarString(named.get("name").value)
+		switch {
+		case !inHead:
+			d.add(true, "parameter-removed", paramLocation, "parameter was removed")
+		case !inBase:
+			if headParam.get("required").value == true {
+				d.add(true, "required-parameter-added", paramLocation, "new required parameter")
+			} else {
+				d.add(false, "parameter-added", paramLocation, "new optional parameter")
+			}
+		default:
+			wasRequired := baseParam.get("required").value == true
+			isRequired := headParam.get("required").value == true
+			if !wasRequired && isRequired {
+				d.add(true, "parameter-became-required", paramLocation, "parameter became required")
+			} else if wasRequired && !isRequired {
+				d.add(false, "parameter-became-optional", paramLocation, "parameter became optional")
+			}
+			d.compareSchema(paramLocation, baseParam.get("schema"), headParam.get("schema"), "request", 0)
+		}
+	}
+
+	baseBody, headBody := baseOp.get("requestBody"), headOp.get("requestBody")
+	switch {
+	case !baseBody.present() && !headBody.present():
+	case !headBody.present():
+		d.add(true, "request-body-removed", location, "request body was removed")
+	case !baseBody.present():
+		if headBody.get("required").value == true {
+			d.add(true, "required-request-body-added", location, "new required request body")
+		} else {
+			d.add(false, "request-body-added", location, "new optional request body")
+		}
+	default:
+		if baseBody.get("required").value != true && headBody.get("required").value == true {
+			d.add(true, "request-body-became-required", location, "request body became required")
+		}
+		d.compareContent(location+" request", baseBody.get("content"), headBody.get("content"), "request")
+	}
+
+	baseResponses := baseOp.get("responses").mapping()
+	headResponses := headOp.get("responses").mapping()
+	for _, code := range unionKeys(baseResponses, headResponses) {
+		responseLocation := location + " response " + code
+		_, inBase := baseResponses[code]
+		_, inHead := headResponses[code]
+		switch {
+		case !inHead:
+			d.add(true, "response-removed", responseLocation, "response was removed")
+		case !inBase:
+			d.add(false, "response-added", responseLocation, "response was added")
+		default:
+			d.compareContent(responseLocation, baseOp.get("responses").get(code).get("content"), headOp.get("responses").get(code).get("content"), "response")
+		}
+	}
+}
+
+// operationParameters merges path-level and operation-level parameters keyed
+// by in:name; operation entries override path-level ones. Path parameters are
+// keyed by their position in the route so renames are not removals.
+func operationParameters(route string, item, op contractNode) map[string]any {
+	position := map[string]int{}
+	for i, match := range pathTemplate.FindAllString(route, -1) {
+		position[strings.Trim(match, "{}")] = i
+	}
+	out := map[string]any{}
+	for _, holder := range []contractNode{item, op} {
+		list, _ := holder.get("parameters").resolve().value.([]any)
+		for _, raw := range list {
+			param := contractNode{src: holder.src, file: holder.get("parameters").file, value: raw}.resolve()
+			name := scalarString(param.get("name").value)
+			in := scalarString(param.get("in").value)
+			if index, ok := position[name]; ok && in == "path" {
+				out["path:#"+strconv.Itoa(index)] = param
+			} else if name != "" && in != "" {
+				out[in+":"+name] = param
+			}
+		}
+	}
+	return out
+}
+
+func (d *contractDiff) compareContent(location string, base, head contractNode, direction string) {
+	baseTypes, headTypes := base.mapping(), head.mapping()
+	for _, mediaType := range unionKeys(baseTypes, headTypes) {
+		_, inBase := baseTypes[mediaType]
+		_, inHead := headTypes[mediaType]
+		switch {
+		case !inHead:
+			d.add(true, direction+"-media-type-removed", location+" "+mediaType, "media type was removed")
+		case !inBase:
+			d.add(false, direction+"-media-type-added", location+" "+mediaType, "media type was added")
+		default:
+			d.compareSchema(location+" "+mediaType, base.get(mediaType).get("schema"), head.get(mediaType).get("schema"), direction, 0)
+		}
+	}
+}
+
+// compareSchema classifies schema changes by direction: requests break when
+// they accept less (narrowed enum, new required property), responses break
+// when they promise less or return more (removed property, widened enum).
+func (d *contractDiff) compareSchema(location string, base, head contractNode, direction string, depth int) {
+	base, head = base.resolve(), head.resolve()
+	baseSchema, _ := base.value.(map[string]any)
+	headSchema, _ := head.value.(map[string]any)
+	if baseSchema == nil || headSchema == nil || depth > 32 {
+		return
+	}
+	if base.ref != "" && head.ref != "" {
+		key := base.ref + "|" + head.ref + "|" + direction
+		if d.seen[key] {
+			return
+		}
+		d.seen[key] = true
+	}
+	request := direction == "request"
+
+	baseTypes, headTypes := schemaTypes(baseSchema), schemaTypes(headSchema)
+	if len(baseTypes) > 0 && len(headTypes) > 0 && !sameSet(baseTypes, headTypes) {
+		widened := subset(baseTypes, headTypes)
+		narrowed := subset(headTypes, baseTypes)
+		switch {
+		case request && widened, !request && narrowed:
+			d.add(false, "type-changed", location, "type changed from %s to %s", joinSet(baseTypes), joinSet(headTypes))
+		default:
+			d.add(true, "type-changed", location, "type changed from %s to %s", joinSet(baseTypes), joinSet(headTypes))
+		}
+	}
+	if baseFormat, headFormat := scalarString(baseSchema["format"]), scalarString(headSchema["format"]); baseFormat != headFormat && baseFormat != "" && headFormat != "" {
+		d.add(true, "format-changed", location, "format changed from %s to %s", baseFormat, headFormat)
+	}
+
+	baseEnum, hasBaseEnum := enumSet(baseSchema)
+	headEnum, hasHeadEnum := enumSet(headSchema)
+	switch {
+	case hasBaseEnum && hasHeadEnum:
+		removed, added := difference(baseEnum, headEnum), difference(headEnum, baseEnum)
+		if len(removed) > 0 {
+			d.add(request, "enum-narrowed", location, "enum values removed: %s", strings.Join(removed, ", "))
+		}
+		if len(added) > 0 {
+			d.add(!request, "enum-widened", location, "enum values added: %s", strings.Join(added, ", "))
+		}
+	case !hasBaseEnum && hasHeadEnum:
+		d.add(request, "enum-added", location, "values are now restricted to an enum")
+	case hasBaseEnum && !hasHeadEnum:
+		d.add(!request, "enum-removed", location, "enum restriction was removed")
+	}
+
+	baseRequired, headRequired := stringSet(baseSchema["required"]), stringSet(headSchema["required"])
+	for _, name := range difference(headRequired, baseRequired) {
+		d.add(request, "property-became-required", location+"."+name, "property became required")
+	}
+	for _, name := range difference(baseRequired, headRequired) {
+		d.add(!request, "property-no-longer-required", location+"."+name, "property is no longer required")
+	}
+
+	baseProps, headProps := base.get("properties"), head.get("properties")
+	baseNames, headNames := baseProps.mapping(), headProps.mapping()
+	for _, name := range unionKeys(baseNames, headNames) {
+		_, inBase := baseNames[name]
+		_, inHead := headNames[name]
+		switch {
+		case !inHead:
+			d.add(!request, "property-removed", location+"."+name, "property was removed")
+		case !inBase:
+			if !request || !headRequired[name] {
+				d.add(false, "property-added", location+"."+name, "property was added")
+			}
+		default:
+			d.compareSchema(location+"."+name, baseProps.get(name), headProps.get(name), direction, depth+1)
+		}
+	}
+	if base.get("items").present() && head.get("items").present() {
+		d.compareSchema(location+"[]", base.get("items"), head.get("items"), direction, depth+1)
+	}
+	for _, keyword := range []string{"allOf", "oneOf", "anyOf"} {
+		baseList, _ := baseSchema[keyword].([]any)
+		headList, _ := headSchema[keyword].([]any)
+		if len(baseList) != len(headList) {
+			d.add(true, "schema-composition-changed", location, "%s changed from %d to %d schemas", keyword, len(baseList), len(headList))
+			continue
+		}
+		for i := range baseList {
+			d.compareSchema(fmt.Sprintf("%s %s[%d]", location, keyword, i), contractNode{src: base.src, file: base.file, value: baseList[i]}, contractNode{src: head.src, file: head.file, value: headList[i]}, direction, depth+1)
+		}
+	}
+}
+
+func schemaTypes(schema map[string]any) map[string]bool {
+	switch typed := schema["type"].(type) {
+	case string:
+		return map[string]bool{typed: true}
+	case []any:
+		return stringSet(typed)
+	}
+	return map[string]bool{}
+}
+
+func enumSet(schema map[string]any) (map[string]bool, bool) {
+	list, ok := schema["enum"].([]any)
+	if !ok {
+		return nil, false
+	}
+	out := map[string]bool{}
+	for _, value := range list {
+		out[fmt.Sprint(value)] = true
+	}
+	return out, true
+}
+
+func stringSet(value any) map[string]bool {
+	out := map[string]bool{}
+	list, _ := value.([]any)
+	for _, item := range list {
+		if text := scalarString(item); text != "" {
+			out[text] = true
+		}
+	}
+	return out
+}
+
+func sameSet(a, b map[string]bool) bool {
+	return subset(a, b) && subset(b, a)
+}
+
+// subset reports whether every member of a is in b.
+func subset(a, b map[string]bool) bool {
+	for key := range a {
+		if !b[key] {
+			return false
+		}
+	}
+	return true
+}
+
+// difference lists members of a missing from b, sorted.
+func difference(a, b map[string]bool) []string {
+	out := []string{}
+	for key := range a {
+		if !b[key] {
+			out = append(out, key)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func joinSet(values map[string]bool) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, "|")
+}
+
+func unionKeys(a, b map[string]any) []string {
+	seen := map[string]bool{}
+	keys := []string{}
+	for _, values := range []map[string]any{a, b} {
+		for key := range values {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func scalarString(value any) string {
+	switch typed := value.(type) {
+	case nil, map[string]any, []any:
+		return ""
+	case string:
+		return strings.TrimSpace(typed)
+	default:
+		return strings.TrimSpace(fmt.Sprint(typed))
+	}
+}
+
+func resolvePath(root, value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" || filepath.IsAbs(value) {
+		return value
+	}
+	return filepath.Join(root, filepath.FromSlash(value))
+}
+
+func exists(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return !info.IsDir()
+}
+
+func printBlocked(issues []string, details map[string]any) {
+	payload := map[string]any{
+		"status": "BLOCKED",
+		"issues": issues,
+	}
+	for k, v := range details {
+		payload[k] = v
+	}
+	data, _ := json.Marshal(payload)
+	fmt.Println(string(data))
+}
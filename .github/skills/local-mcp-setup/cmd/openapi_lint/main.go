@@ -0,0 +1,957 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	targetRoot := flag.String("target-root", "", "target repository root (defaults to cwd)")
+	scanDirs := flag.String("scan-dirs", "docs/openapi,repos/*/openapi", "comma-separated directories (relative to target root, * allowed) scanned for OpenAPI documents")
+	files := flag.String("files", "", "comma-separated OpenAPI documents to lint instead of scanning")
+	corporateTechFile := flag.String("corporate-tech-file", ".github/skills/local-mcp-setup/corporate-approved-tech.json", "corporate approved tech baseline JSON providing decisions.openapi_version; a missing default file uses 3.1")
+	decode := flag.String("decode", "", "decode one document read from stdin (the value is its file name, used for the format) and print it as JSON instead of linting")
+	flag.Parse()
+
+	if *decode != "" {
+		decodeDocument(*decode)
+		return
+	}
+
+	root := strings.TrimSpace(*targetRoot)
+	if root == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			printBlocked([]string{"unable to determine working directory"}, nil)
+			return
+		}
+		root = cwd
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		printBlocked([]string{"invalid target root"}, nil)
+		return
+	}
+
+	baseline, baselineSource, err := loadOpenAPIBaseline(absRoot, strings.TrimSpace(*corporateTechFile), flagSet("corporate-tech-file"))
+	if err != nil {
+		printBlocked([]string{fmt.Sprintf("invalid corporate tech baseline: %v", err)}, nil)
+		return
+	}
+
+	candidates := []string{}
+	if strings.TrimSpace(*files) != "" {
+		for _, file := range strings.Split(*files, ",") {
+			if file = strings.TrimSpace(file); file == "" {
+				continue
+			}
+			path := file
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(absRoot, filepath.FromSlash(path))
+			}
+			if _, statErr := os.Stat(path); statErr != nil {
+				printBlocked([]string{fmt.Sprintf("OpenAPI document not found: %s", file)}, nil)
+				return
+			}
+			candidates = append(candidates, path)
+		}
+	} else {
+		for _, dir := range strings.Split(*scanDirs, ",") {
+			if dir = strings.TrimSpace(dir); dir == "" {
+				continue
+			}
+			matches, _ := filepath.Glob(filepath.Join(absRoot, filepath.FromSlash(dir)))
+			for _, match := range matches {
+				candidates = append(candidates, openAPIFiles(match)...)
+			}
+		}
+	}
+
+	validator := newOpenAPIValidator(baseline)
+	checked := []string{}
+	fragments := []string{}
+	findings := []openapiFinding{}
+	for _, path := range candidates {
+		rel := relPath(absRoot, path)
+		// Documents without an openapi/swagger key are $ref targets
+		// (shared schemas); they are checked through the documents using them.
+		if !validator.isRoot(path) && strings.TrimSpace(*files) == "" {
+			fragments = append(fragments, rel)
+			continue
+		}
+		checked = append(checked, rel)
+		for _, finding := range validator.validate(path) {
+			finding.File = relPath(absRoot, finding.File)
+			findings = append(findings, finding)
+		}
+	}
+
+	details := map[string]any{
+		"baseline":          baseline,
+		"baseline_source":   baselineSource,
+		"documents_checked": checked,
+		"fragments":         fragments,
+	}
+	if len(checked) == 0 {
+		printBlocked([]string{"no OpenAPI documents found"}, details)
+		return
+	}
+	if len(findings) > 0 {
+		details["findings"] = findings
+		printBlocked([]string{"OpenAPI contract findings"}, details)
+		return
+	}
+
+	details["status"] = "PASS"
+	payload, _ := json.Marshal(details)
+	fmt.Println(string(payload))
+}
+
+// decodeDocument prints a YAML or JSON document from stdin as JSON so other
+// commands (openapi_breaking_check) reuse this parser instead of carrying one.
+func decodeDocument(name string) {
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		printBlocked([]string{fmt.Sprintf("failed to read %s from stdin: %v", name, err)}, nil)
+		return
+	}
+	doc, err := parseOpenAPIData(name, data)
+	if err != nil {
+		printBlocked([]string{fmt.Sprintf("failed to parse %s: %v", name, err)}, nil)
+		return
+	}
+	payload, _ := json.Marshal(map[string]any{"status": "PASS", "document": doc})
+	fmt.Println(string(payload))
+}
+
+// openAPIFiles lists YAML and JSON files at or below path.
+func openAPIFiles(path string) []string {
+	out := []string{}
+	_ = filepath.WalkDir(path, func(p string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(p)) {
+		case ".yaml", ".yml", ".json":
+			out = append(out, p)
+		}
+		return nil
+	})
+	sort.Strings(out)
+	return out
+}
+
+func relPath(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+func printBlocked(issues []string, details map[string]any) {
+	payload := map[string]any{
+		"status": "BLOCKED",
+		"issues": issues,
+	}
+	for k, v := range details {
+		payload[k] = v
+	}
+	data, _ := json.Marshal(payload)
+	fmt.Println(string(data))
+}
+
+// defaultOpenAPIBaseline is the contract format mandated by ADR-POC-002.
+const defaultOpenAPIBaseline = "3.1"
+
+type openapiFinding struct {
+	Rule     string `json:"rule"`
+	File     string `json:"file"`
+	Location string `json:"location"`
+	Message  string `json:"message"`
+}
+
+// loadOpenAPIBaseline reads decisions.openapi_version (major.minor) from the
+// corporate approved tech baseline.
+func loadOpenAPIBaseline(root, value string, explicit bool) (string, string, error) {
+	if value == "" {
+		return defaultOpenAPIBaseline, "built-in", nil
+	}
+	path := value
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, filepath.FromSlash(path))
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if explicit {
+			return "", "", err
+		}
+		return defaultOpenAPIBaseline, "built-in", nil
+	}
+	var baseline struct {
+		Decisions struct {
+			OpenAPIVersion string `json:"openapi_version"`
+		} `json:"decisions"`
+	}
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return "", "", err
+	}
+	version := strings.TrimSpace(baseline.Decisions.OpenAPIVersion)
+	if version == "" {
+		return defaultOpenAPIBaseline, filepath.ToSlash(path), nil
+	}
+	if !openapiBaselinePattern.MatchString(version) {
+		return "", "", fmt.Errorf("decisions.openapi_version %q is not major.minor", version)
+	}
+	parts := strings.Split(version, ".")
+	return parts[0] + "." + parts[1], filepath.ToSlash(path), nil
+}
+
+var (
+	openapiBaselinePattern = regexp.MustCompile(`^\d+\.\d+(\.\d+)?$`)
+	openapiVersionPattern  = regexp.MustCompile(`^(\d+)\.(\d+)\.\d+$`)
+	openapiPathTemplate    = regexp.MustCompile(`\{([^}/]+)\}`)
+	openapiResponseCode    = regexp.MustCompile(`^(default|[1-5](\d\d|XX))$`)
+	openapiPlaceholder     = regexp.MustCompile(`(?i)placeholder|<[^>]*>|\b(todo|tbd|fixme|changeme)\b|^\s*(untitled|title|api|my api|sample api|example api|api title)\s*$`)
+	openapiMethods         = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+	openapiParameterIn     = map[string]bool{"query": true, "header": true, "path": true, "cookie": true}
+)
+
+// openapiValidator checks OpenAPI documents: parsing (YAML or JSON), $ref
+// resolution across files, structure, the version baseline and placeholder
+// content. Loaded files are cached so shared fragments parse once.
+type openapiValidator struct {
+	baseline string
+	files    map[string]any
+	errors   map[string]error
+}
+
+func newOpenAPIValidator(baseline string) *openapiValidator {
+	return &openapiValidator{baseline: baseline, files: map[string]any{}, errors: map[string]error{}}
+}
+
+func (v *openapiValidator) load(path string) (any, error) {
+	path = filepath.Clean(path)
+	if doc, ok := v.files[path]; ok {
+		return doc, v.errors[path]
+	}
+	data, err := os.ReadFile(path)
+	var doc any
+	if err == nil {
+		doc, err = parseOpenAPIData(path, data)
+	}
+	v.files[path] = doc
+	v.errors[path] = err
+	return doc, err
+}
+
+// parseOpenAPIData decodes JSON (by extension or a leading brace) or YAML.
+// JSON numbers keep their literal text so both formats compare alike.
+func parseOpenAPIData(path string, data []byte) (any, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.EqualFold(filepath.Ext(path), ".json") || strings.HasPrefix(trimmed, "{") {
+		decoder := json.NewDecoder(strings.NewReader(trimmed))
+		decoder.UseNumber()
+		var doc any
+		if err := decoder.Decode(&doc); err != nil {
+			return nil, err
+		}
+		return normalizeJSONNumbers(doc), nil
+	}
+	return parseYAML(string(data))
+}
+
+func normalizeJSONNumbers(node any) any {
+	switch typed := node.(type) {
+	case map[string]any:
+		for key, value := range typed {
+			typed[key] = normalizeJSONNumbers(value)
+		}
+	case []any:
+		for i, value := range typed {
+			typed[i] = normalizeJSONNumbers(value)
+		}
+	case json.Number:
+		return typed.String()
+	}
+	return node
+}
+
+// isRoot reports whether path is an OpenAPI (or Swagger) document rather than
+// a fragment referenced from one.
+func (v *openapiValidator) isRoot(path string) bool {
+	doc, err := v.load(path)
+	if err != nil {
+		return true
+	}
+	top, ok := doc.(map[string]any)
+	if !ok {
+		return false
+	}
+	_, openapi := top["openapi"]
+	_, swagger := top["swagger"]
+	return openapi || swagger
+}
+
+func (v *openapiValidator) validate(path string) []openapiFinding {
+	findings := []openapiFinding{}
+	add := func(file, location, rule, format string, args ...any) {
+		findings = append(findings, openapiFinding{Rule: rule, File: file, Location: location, Message: fmt.Sprintf(format, args...)})
+	}
+	doc, err := v.load(path)
+	if err != nil {
+		add(path, "#", "parse-error", "cannot parse document: %v", err)
+		return findings
+	}
+	top, ok := doc.(map[string]any)
+	if !ok {
+		add(path, "#", "invalid-document", "document root must be a mapping")
+		return findings
+	}
+
+	if _, ok := top["swagger"]; ok {
+		add(path, "#/swagger", "unsupported-version", "Swagger 2.0 documents are not supported; migrate to OpenAPI %s", v.baseline)
+	} else if version := scalarString(top["openapi"]); version == "" {
+		add(path, "#/openapi", "missing-version", "openapi version field is missing")
+	} else if match := openapiVersionPattern.FindStringSubmatch(version); match == nil {
+		add(path, "#/openapi", "invalid-version", "openapi version %q is not major.minor.patch", version)
+	} else if match[1]+"."+match[2] != v.baseline {
+		add(path, "#/openapi", "version-baseline", "openapi %s does not match the approved baseline %s.x", version, v.baseline)
+	}
+
+	info, ok := top["info"].(map[string]any)
+	if !ok {
+		add(path, "#/info", "missing-info", "info object is missing")
+	} else {
+		title := scalarString(info["title"])
+		switch {
+		case title == "":
+			add(path, "#/info/title", "missing-title", "info.title is missing")
+		case openapiPlaceholder.MatchString(title):
+			add(path, "#/info/title", "placeholder-title", "info.title %q is a placeholder", title)
+		}
+		if scalarString(info["version"]) == "" {
+			add(path, "#/info/version", "missing-info-version", "info.version is missing")
+		}
+	}
+
+	paths, isMap := top["paths"].(map[string]any)
+	switch {
+	case top["paths"] != nil && !isMap:
+		add(path, "#/paths", "invalid-paths", "paths must be a mapping")
+	case len(paths) == 0:
+		add(path, "#/paths", "empty-paths", "paths is empty; the contract defines no operations")
+	}
+	operationIDs := map[string]string{}
+	for _, route := range sortedKeys(paths) {
+		location := "#/paths/" + escapePointer(route)
+		if !strings.HasPrefix(route, "/") {
+			add(path, location, "invalid-path", "path %q must start with /", route)
+		}
+		item, itemFile, ok := v.resolveMap(path, paths[route])
+		if !ok {
+			add(path, location, "invalid-path-item", "path item must be a mapping")
+			continue
+		}
+		shared := v.parameters(itemFile, item["parameters"], location+"/parameters", add)
+		templated := map[string]bool{}
+		templateNames := []string{}
+		for _, match := range openapiPathTemplate.FindAllStringSubmatch(route, -1) {
+			if !templated[match[1]] {
+				templateNames = append(templateNames, match[1])
+			}
+			templated[match[1]] = true
+		}
+		for _, method := range openapiMethods {
+			rawOp, present := item[method]
+			if !present {
+				continue
+			}
+			opLocation := location + "/" + method
+			op, ok := rawOp.(map[string]any)
+			if !ok {
+				add(path, opLocation, "invalid-operation", "operation must be a mapping")
+				continue
+			}
+			if id := scalarString(op["operationId"]); id != "" {
+				if previous, seen := operationIDs[id]; seen {
+					add(path, opLocation+"/operationId", "duplicate-operation-id", "operationId %q is also used by %s", id, previous)
+				} else {
+					operationIDs[id] = opLocation
+				}
+			}
+			responses, ok := op["responses"].(map[string]any)
+			if !ok || len(responses) == 0 {
+				add(path, opLocation+"/responses", "missing-responses", "operation defines no responses")
+			}
+			for _, code := range sortedKeys(responses) {
+				if !openapiResponseCode.MatchString(code) {
+					add(path, opLocation+"/responses/"+escapePointer(code), "invalid-response-code", "response key %q is not an HTTP status code, range (2XX) or default", code)
+				}
+			}
+			// Operation parameters override path-level ones with the same
+			// location and name.
+			params := map[string]any{}
+			for key, param := range shared {
+				params[key] = param
+			}
+			for key, param := range v.parameters(path, op["parameters"], opLocation+"/parameters", add) {
+				params[key] = param
+			}
+			for _, key := range sortedKeys(params) {
+				param := params[key].(map[string]any)
+				if scalarString(param["in"]) != "path" {
+					continue
+				}
+				name := scalarString(param["name"])
+				if !templated[name] {
+					add(path, opLocation, "unused-path-parameter", "path parameter %q does not appear in %s", name, route)
+				}
+				if param["required"] != true {
+					add(path, opLocation, "optional-path-parameter", "path parameter %q must be required: true", name)
+				}
+			}
+			for _, name := range templateNames {
+				if _, ok := params["path:"+name]; !ok {
+					add(path, opLocation, "missing-path-parameter", "path template {%s} has no matching path parameter", name)
+				}
+			}
+		}
+	}
+
+	visited := map[string]bool{}
+	v.checkRefs(path, doc, "#", visited, add)
+	return findings
+}
+
+// parameters resolves a parameter list and returns it keyed by in:name.
+func (v *openapiValidator) parameters(file string, raw any, location string, add func(file, location, rule, format string, args ...any)) map[string]map[string]any {
+	out := map[string]map[string]any{}
+	list, ok := raw.([]any)
+	if raw != nil && !ok {
+		add(file, location, "invalid-parameters", "parameters must be a list")
+		return out
+	}
+	for i, item := range list {
+		itemLocation := location + "/" + strconv.Itoa(i)
+		param, _, ok := v.resolveMap(file, item)
+		if !ok {
+			continue
+		}
+		name := scalarString(param["name"])
+		in := scalarString(param["in"])
+		if name == "" {
+			add(file, itemLocation, "invalid-parameter", "parameter has no name")
+			continue
+		}
+		if !openapiParameterIn[in] {
+			add(file, itemLocation, "invalid-parameter", "parameter %q has invalid location %q (query, header, path, cookie)", name, in)
+			continue
+		}
+		out[in+":"+name] = param
+	}
+	return out
+}
+
+// resolveMap follows $ref chains to a mapping, returning it with the file it
+// lives in. Unresolvable refs return false; checkRefs reports them.
+func (v *openapiValidator) resolveMap(file string, node any) (map[string]any, string, bool) {
+	for depth := 0; depth < 16; depth++ {
+		item, ok := node.(map[string]any)
+		if !ok {
+			return nil, file, false
+		}
+		ref := scalarString(item["$ref"])
+		if ref == "" {
+			return item, file, true
+		}
+		target, targetFile, err := v.resolveRef(file, ref)
+		if err != nil {
+			return nil, file, false
+		}
+		node, file = target, targetFile
+	}
+	return nil, file, false
+}
+
+// resolveRef resolves a local (#/...) or relative-file ($ref: schemas.yaml#/Pet)
+// reference. Remote URLs are not fetched.
+func (v *openapiValidator) resolveRef(file, ref string) (any, string, error) {
+	if strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") {
+		return nil, file, fmt.Errorf("remote references are not resolved")
+	}
+	target, fragment, _ := strings.Cut(ref, "#")
+	targetFile := file
+	if target != "" {
+		targetFile = filepath.Join(filepath.Dir(file), filepath.FromSlash(target))
+	}
+	doc, err := v.load(targetFile)
+	if err != nil {
+		return nil, targetFile, fmt.Errorf("cannot load %s: %v", target, err)
+	}
+	node := doc
+	if fragment == "" || fragment == "/" {
+		return node, targetFile, nil
+	}
+	if !strings.HasPrefix(fragment, "/") {
+		return nil, targetFile, fmt.Errorf("fragment %q is not a JSON pointer", fragment)
+	}
+	for _, token := range strings.Split(fragment[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch typed := node.(type) {
+		case map[string]any:
+			next, ok := typed[token]
+			if !ok {
+				return nil, targetFile, fmt.Errorf("%q not found", token)
+			}
+			node = next
+		case []any:
+			index, convErr := strconv.Atoi(token)
+			if convErr != nil || index < 0 || index >= len(typed) {
+				return nil, targetFile, fmt.Errorf("index %q out of range", token)
+			}
+			node = typed[index]
+		default:
+			return nil, targetFile, fmt.Errorf("%q not found", token)
+		}
+	}
+	return node, targetFile, nil
+}
+
+// checkRefs reports every $ref that does not resolve, following refs into
+// other files once so their own refs are checked too.
+func (v *openapiValidator) checkRefs(file string, node any, location string, visited map[string]bool, add func(file, location, rule, format string, args ...any)) {
+	switch typed := node.(type) {
+	case map[string]any:
+		if ref := scalarString(typed["$ref"]); ref != "" {
+			target, targetFile, err := v.resolveRef(file, ref)
+			if err != nil {
+				add(file, location+"/$ref", "unresolved-ref", "$ref %q does not resolve: %v", ref, err)
+			} else if targetFile != filepath.Clean(file) && !visited[targetFile+"#"+ref] {
+				visited[targetFile+"#"+ref] = true
+				_, fragment, _ := strings.Cut(ref, "#")
+				v.checkRefs(targetFile, target, "#"+fragment, visited, add)
+			}
+		}
+		for _, key := range sortedKeys(typed) {
+			if key == "$ref" {
+				continue
+			}
+			v.checkRefs(file, typed[key], location+"/"+escapePointer(key), visited, add)
+		}
+	case []any:
+		for i, item := range typed {
+			v.checkRefs(file, item, location+"/"+strconv.Itoa(i), visited, add)
+		}
+	}
+}
+
+func scalarString(value any) string {
+	switch typed := value.(type) {
+	case nil, map[string]any, []any:
+		return ""
+	case string:
+		return strings.TrimSpace(typed)
+	default:
+		return strings.TrimSpace(fmt.Sprint(typed))
+	}
+}
+
+func sortedKeys(values map[string]any) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+type yamlLine struct {
+	number int
+	indent int
+	text   string
+	raw    string
+}
+
+// parseYAML decodes the YAML subset used by governance files: block mappings
+// and sequences, flow collections, quoted and plain scalars, and literal or
+// folded block scalars. Mappings decode to map[string]any, sequences to []any,
+// booleans to bool, null to nil, and every other scalar to its string form.
+func parseYAML(data string) (any, error) {
+	raw := strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n")
+	lines := []yamlLine{}
+	for i, line := range raw {
+		if strings.TrimSpace(line) == "---" && len(lines) == 0 {
+			continue
+		}
+		text := stripYAMLComment(line)
+		if strings.TrimSpace(text) == "" {
+			lines = append(lines, yamlLine{number: i + 1, indent: -1, raw: line})
+			continue
+		}
+		indent := len(text) - len(strings.TrimLeft(text, " "))
+		lines = append(lines, yamlLine{number: i + 1, indent: indent, text: strings.TrimSpace(text), raw: line})
+	}
+	p := &yamlParser{lines: lines}
+	p.skipBlank()
+	if p.pos >= len(p.lines) {
+		return nil, nil
+	}
+	value, err := p.parseNode(p.lines[p.pos].indent)
+	if err != nil {
+		return nil, err
+	}
+	p.skipBlank()
+	if p.pos < len(p.lines) {
+		return nil, fmt.Errorf("line %d: unexpected indentation", p.lines[p.pos].number)
+	}
+	return value, nil
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+func (p *yamlParser) skipBlank() {
+	for p.pos < len(p.lines) && p.lines[p.pos].indent < 0 {
+		p.pos++
+	}
+}
+
+func (p *yamlParser) parseNode(indent int) (any, error) {
+	line := p.lines[p.pos]
+	if line.text == "-" || strings.HasPrefix(line.text, "- ") {
+		return p.parseSequence(indent)
+	}
+	return p.parseMapping(indent)
+}
+
+func (p *yamlParser) parseSequence(indent int) (any, error) {
+	out := []any{}
+	for {
+		p.skipBlank()
+		if p.pos >= len(p.lines) {
+			return out, nil
+		}
+		line := p.lines[p.pos]
+		if line.indent != indent || !(line.text == "-" || strings.HasPrefix(line.text, "- ")) {
+			if line.indent > indent {
+				return nil, fmt.Errorf("line %d: unexpected indentation", line.number)
+			}
+			return out, nil
+		}
+		rest := strings.TrimSpace(strings.TrimPrefix(line.text, "-"))
+		if rest == "" {
+			p.pos++
+			value, err := p.parseChild(indent)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, value)
+			continue
+		}
+		if _, _, ok := splitYAMLKey(rest); ok || rest == "-" || strings.HasPrefix(rest, "- ") {
+			// "- key: value" opens a nested node aligned with its first key.
+			p.lines[p.pos] = yamlLine{number: line.number, indent: indent + len(line.text) - len(rest), text: rest, raw: line.raw}
+			value, err := p.parseNode(p.lines[p.pos].indent)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, value)
+			continue
+		}
+		value, err := parseYAMLScalar(rest, line.number)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, value)
+		p.pos++
+	}
+}
+
+func (p *yamlParser) parseMapping(indent int) (any, error) {
+	out := map[string]any{}
+	for {
+		p.skipBlank()
+		if p.pos >= len(p.lines) {
+			return out, nil
+		}
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			return out, nil
+		}
+		if line.indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", line.number)
+		}
+		if line.text == "-" || strings.HasPrefix(line.text, "- ") {
+			return out, nil
+		}
+		key, rest, ok := splitYAMLKey(line.text)
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key: value", line.number)
+		}
+		p.pos++
+		switch {
+		case rest == "":
+			value, err := p.parseChild(indent)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = value
+		case strings.HasPrefix(rest, "|") || strings.HasPrefix(rest, ">"):
+			out[key] = p.parseBlockScalar(indent, rest)
+		default:
+			value, err := parseYAMLScalar(rest, line.number)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = value
+		}
+	}
+}
+
+// parseChild parses the value of a key or "-" with nothing after it: a deeper
+// indented node, a sequence at the parent's indent, or null.
+func (p *yamlParser) parseChild(indent int) (any, error) {
+	p.skipBlank()
+	if p.pos >= len(p.lines) {
+		return nil, nil
+	}
+	next := p.lines[p.pos]
+	if next.indent > indent {
+		return p.parseNode(next.indent)
+	}
+	if next.indent == indent && (next.text == "-" || strings.HasPrefix(next.text, "- ")) {
+		return p.parseSequence(indent)
+	}
+	return nil, nil
+}
+
+// parseBlockScalar reads a literal (|) or folded (>) scalar from the original
+// lines so "#" inside the text is kept.
+func (p *yamlParser) parseBlockScalar(indent int, header string) string {
+	parts := []string{}
+	blockIndent := -1
+	for p.pos < len(p.lines) {
+		raw := strings.TrimRight(p.lines[p.pos].raw, " \t")
+		if strings.TrimSpace(raw) == "" {
+			parts = append(parts, "")
+			p.pos++
+			continue
+		}
+		rawIndent := len(raw) - len(strings.TrimLeft(raw, " "))
+		if rawIndent <= indent {
+			break
+		}
+		if blockIndent < 0 {
+			blockIndent = rawIndent
+		}
+		if rawIndent < blockIndent {
+			rawIndent = blockIndent
+		}
+		parts = append(parts, strings.Repeat(" ", rawIndent-blockIndent)+strings.TrimLeft(raw, " "))
+		p.pos++
+	}
+	for len(parts) > 0 && parts[len(parts)-1] == "" {
+		parts = parts[:len(parts)-1]
+	}
+	if strings.HasPrefix(header, ">") {
+		return strings.Join(parts, " ") + "\n"
+	}
+	return strings.Join(parts, "\n") + "\n"
+}
+
+func splitYAMLKey(text string) (string, string, bool) {
+	if strings.HasPrefix(text, "\"") || strings.HasPrefix(text, "'") {
+		quote := text[0]
+		end := strings.IndexByte(text[1:], quote)
+		if end < 0 {
+			return "", "", false
+		}
+		key := text[1 : end+1]
+		rest := strings.TrimSpace(text[end+2:])
+		if !strings.HasPrefix(rest, ":") {
+			return "", "", false
+		}
+		return key, strings.TrimSpace(rest[1:]), true
+	}
+	if strings.HasPrefix(text, "[") || strings.HasPrefix(text, "{") {
+		return "", "", false
+	}
+	idx := strings.Index(text, ": ")
+	if idx < 0 {
+		if strings.HasSuffix(text, ":") {
+			idx = len(text) - 1
+		} else {
+			return "", "", false
+		}
+	}
+	return strings.TrimSpace(text[:idx]), strings.TrimSpace(text[idx+1:]), true
+}
+
+func stripYAMLComment(line string) string {
+	inSingle, inDouble := false, false
+	for i, r := range line {
+		switch r {
+		case '\'':
+			if !inDouble {
+				inSingle = !inSingle
+			}
+		case '"':
+			if !inSingle {
+				inDouble = !inDouble
+			}
+		case '#':
+			if !inSingle && !inDouble && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t') {
+				return strings.TrimRight(line[:i], " \t")
+			}
+		}
+	}
+	return strings.TrimRight(line, " \t")
+}
+
+func parseYAMLScalar(text string, lineNo int) (any, error) {
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "[") || strings.HasPrefix(text, "{") {
+		value, rest, err := parseYAMLFlow(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNo, err)
+		}
+		if strings.TrimSpace(rest) != "" {
+			return nil, fmt.Errorf("line %d: unexpected %q after flow collection", lineNo, rest)
+		}
+		return value, nil
+	}
+	return yamlPlainScalar(text), nil
+}
+
+func yamlPlainScalar(text string) any {
+	switch {
+	case strings.HasPrefix(text, "\"") && strings.HasSuffix(text, "\"") && len(text) >= 2:
+		if unquoted, err := strconv.Unquote(text); err == nil {
+			return unquoted
+		}
+		return text[1 : len(text)-1]
+	case strings.HasPrefix(text, "'") && strings.HasSuffix(text, "'") && len(text) >= 2:
+		return strings.ReplaceAll(text[1:len(text)-1], "''", "'")
+	}
+	switch strings.ToLower(text) {
+	case "", "~", "null":
+		return nil
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	return text
+}
+
+func parseYAMLFlow(text string) (any, string, error) {
+	text = strings.TrimLeft(text, " ")
+	if text == "" {
+		return nil, "", fmt.Errorf("unterminated flow collection")
+	}
+	switch text[0] {
+	case '[':
+		out := []any{}
+		rest := strings.TrimLeft(text[1:], " ")
+		if strings.HasPrefix(rest, "]") {
+			return out, rest[1:], nil
+		}
+		for {
+			value, remaining, err := parseYAMLFlow(rest)
+			if err != nil {
+				return nil, "", err
+			}
+			out = append(out, value)
+			remaining = strings.TrimLeft(remaining, " ")
+			if strings.HasPrefix(remaining, ",") {
+				rest = strings.TrimLeft(remaining[1:], " ")
+				continue
+			}
+			if strings.HasPrefix(remaining, "]") {
+				return out, remaining[1:], nil
+			}
+			return nil, "", fmt.Errorf("expected , or ] in flow sequence")
+		}
+	case '{':
+		out := map[string]any{}
+		rest := strings.TrimLeft(text[1:], " ")
+		if strings.HasPrefix(rest, "}") {
+			return out, rest[1:], nil
+		}
+		for {
+			keyValue, remaining, err := parseYAMLFlow(rest)
+			if err != nil {
+				return nil, "", err
+			}
+			key := fmt.Sprint(keyValue)
+			remaining = strings.TrimLeft(remaining, " ")
+			if !strings.HasPrefix(remaining, ":") {
+				return nil, "", fmt.Errorf("expected : in flow mapping")
+			}
+			value, after, err := parseYAMLFlow(strings.TrimLeft(remaining[1:], " "))
+			if err != nil {
+				return nil, "", err
+			}
+			out[key] = value
+			after = strings.TrimLeft(after, " ")
+			if strings.HasPrefix(after, ",") {
+				rest = strings.TrimLeft(after[1:], " ")
+				continue
+			}
+			if strings.HasPrefix(after, "}") {
+				return out, after[1:], nil
+			}
+			return nil, "", fmt.Errorf("expected , or } in flow mapping")
+		}
+	case '"', '\'':
+		quote := text[0]
+		for i := 1; i < len(text); i++ {
+			if quote == '"' && text[i] == '\\' {
+				i++
+				continue
+			}
+			if text[i] == quote {
+				if quote == '\'' && i+1 < len(text) && text[i+1] == '\'' {
+					i++
+					continue
+				}
+				return yamlPlainScalar(text[:i+1]), text[i+1:], nil
+			}
+		}
+		return nil, "", fmt.Errorf("unterminated quoted scalar")
+	}
+	end := strings.IndexAny(text, ",]}")
+	colon := strings.Index(text, ": ")
+	if colon >= 0 && (end < 0 || colon < end) {
+		end = colon
+	}
+	if end < 0 {
+		end = len(text)
+	}
+	return yamlPlainScalar(strings.TrimSpace(text[:end])), text[end:], nil
+}
@@ -1,10 +1,12 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 )
@@ -13,6 +15,7 @@ func main() {
 	phase := flag.String("phase", "01", "workflow phase to validate (01-05)")
 	targetRoot := flag.String("target-root", "", "target repository root (defaults to cwd)")
 	enforceSequence := flag.Bool("enforce-sequence", false, "require prior phase gate PASS before validating current phase")
+	openapiLint := flag.String("openapi-lint", "", "openapi_lint command source run for phase 03 (defaults to .github/skills/local-mcp-setup/cmd/openapi_lint/main.go under the working directory, then the target root)")
 	flag.Parse()
 
 	root := strings.TrimSpace(*targetRoot)
@@ -56,9 +59,7 @@ func main() {
 		require("docs/plans/control-applicability-matrix.md")
 		require("docs/handoffs/architect/phase-gate.md")
 		if requiresOpenAPISpec(absRoot) {
-			if !hasOpenAPISpec(absRoot) {
-				missing = append(missing, "docs/openapi/*.yaml")
-			}
+			missing = append(missing, openAPISpecMissing(absRoot, strings.TrimSpace(*openapiLint))...)
 		}
 		if !hasApprovedPlanningSignoff(absRoot) {
 			missing = append(missing, "docs/plans/planning-signoff.md (must include Approval Status: APPROVED)")
@@ -244,22 +245,62 @@ func requiresOpenAPISpec(root string) bool {
 	return strings.Contains(text, "openapi") || strings.Contains(text, "http")
 }
 
-func hasOpenAPISpec(root string) bool {
-	openapiRoot := filepath.Join(root, "docs", "openapi")
-	entries, err := os.ReadDir(openapiRoot)
-	if err != nil {
-		return false
-	}
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
+// openAPISpecMissing runs the openapi_lint command over docs/openapi and
+// reports each finding; at least one document with an openapi key is required.
+func openAPISpecMissing(root, command string) []string {
+	if command == "" {
+		rel := filepath.Join(".github", "skills", "local-mcp-setup", "cmd", "openapi_lint", "main.go")
+		command = filepath.Join(root, rel)
+		if cwd, err := os.Getwd(); err == nil && exists(filepath.Join(cwd, rel)) {
+			command = filepath.Join(cwd, rel)
 		}
-		name := strings.ToLower(entry.Name())
-		if strings.HasSuffix(name, ".yaml") || strings.HasSuffix(name, ".yml") {
-			return true
+	} else if !filepath.IsAbs(command) {
+		command = filepath.Join(root, filepath.FromSlash(command))
+	}
+	if !exists(command) {
+		return []string{"docs/openapi/*.yaml (openapi_lint command not found; set --openapi-lint)"}
+	}
+
+	cmd := exec.Command("go", "run", command, "--target-root", root, "--scan-dirs", "docs/openapi")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	var report struct {
+		Status           string   `json:"status"`
+		Issues           []string `json:"issues"`
+		DocumentsChecked []string `json:"documents_checked"`
+		Findings         []struct {
+			Rule     string `json:"rule"`
+			File     string `json:"file"`
+			Location string `json:"location"`
+			Message  string `json:"message"`
+		} `json:"findings"`
+	}
+	if jsonErr := json.Unmarshal(output, &report); jsonErr != nil {
+		if err == nil {
+			err = jsonErr
 		}
+		return []string{fmt.Sprintf("docs/openapi/*.yaml (openapi_lint failed: %v %s)", err, strings.TrimSpace(stderr.String()))}
 	}
-	return false
+	if len(report.DocumentsChecked) == 0 {
+		return []string{"docs/openapi/*.yaml"}
+	}
+	missing := []string{}
+	for _, finding := range report.Findings {
+		missing = append(missing, fmt.Sprintf("%s (openapi lint %s at %s: %s)", finding.File, finding.Rule, finding.Location, finding.Message))
+	}
+	if report.Status != "PASS" && len(missing) == 0 {
+		missing = append(missing, fmt.Sprintf("docs/openapi/*.yaml (openapi lint: %s)", strings.Join(report.Issues, "; ")))
+	}
+	return missing
+}
+
+func exists(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return !info.IsDir()
 }
 
 func hasApprovedPlanningSignoff(root string) bool {
@@ -0,0 +1,422 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	reqIDPattern  = regexp.MustCompile(`\bREQ-[0-9]+\b`)
+	planIDPattern = regexp.MustCompile(`\bPLAN-[A-Za-z0-9]+\b`)
+)
+
+// planSettings are the plan_* keys of the planning behavior profile.
+type planSettings struct {
+	ProfileFile          string `json:"profile_file"`
+	PlanDirectory        string `json:"plan_directory"`
+	PlanIndexFile        string `json:"plan_index_file"`
+	PlanStoryFilePattern string `json:"plan_story_file_pattern"`
+	TraceabilityRequired bool   `json:"plan_traceability_required"`
+}
+
+type indexRow struct {
+	line       int
+	reqIDs     []string
+	planID     string
+	planFile   string
+	targetRepo string
+	owner      string
+	status     string
+}
+
+type planningFinding struct {
+	Rule    string `json:"rule"`
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"`
+	Message string `json:"message"`
+}
+
+func main() {
+	targetRoot := flag.String("target-root", "", "target repository root (defaults to cwd)")
+	profileFile := flag.String("profile-file", "", "optional explicit planning behavior profile path")
+	requirementsFile := flag.String("requirements-file", "docs/requirements.md", "requirements document that defines REQ IDs")
+	statuses := flag.String("statuses", "DRAFT,IN_REVIEW,APPROVED,IN_PROGRESS,DONE,BLOCKED,SUPERSEDED", "comma-separated valid plan statuses")
+	flag.Parse()
+
+	root := strings.TrimSpace(*targetRoot)
+	if root == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			printBlocked([]string{"unable to determine working directory"}, nil)
+			return
+		}
+		root = cwd
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		printBlocked([]string{"invalid target root"}, nil)
+		return
+	}
+
+	settings, err := loadPlanSettings(absRoot, strings.TrimSpace(*profileFile))
+	if err != nil {
+		printBlocked([]string{fmt.Sprintf("failed to read planning behavior profile: %v", err)}, nil)
+		return
+	}
+	namingRegex := regexp.MustCompile(namingToRegex(settings.PlanStoryFilePattern))
+
+	indexPath := filepath.Join(absRoot, filepath.FromSlash(settings.PlanIndexFile))
+	rows, err := parseIndex(indexPath)
+	if err != nil {
+		printBlocked([]string{fmt.Sprintf("plan index not readable: %s", settings.PlanIndexFile)}, map[string]any{"plan_settings": settings})
+		return
+	}
+
+	requirementsPath := filepath.Join(absRoot, filepath.FromSlash(strings.TrimSpace(*requirementsFile)))
+	knownReqs, reqErr := collectIDs(requirementsPath, reqIDPattern)
+
+	validStatuses := map[string]bool{}
+	for _, status := range strings.Split(*statuses, ",") {
+		if status = strings.ToUpper(strings.TrimSpace(status)); status != "" {
+			validStatuses[status] = true
+		}
+	}
+
+	findings := []planningFinding{}
+	add := func(rule, file string, line int, format string, args ...any) {
+		findings = append(findings, planningFinding{Rule: rule, File: file, Line: line, Message: fmt.Sprintf(format, args...)})
+	}
+	if reqErr != nil {
+		add("requirements-missing", filepath.ToSlash(strings.TrimSpace(*requirementsFile)), 0, "requirements file not readable; REQ IDs cannot be verified")
+	}
+
+	indexed := map[string]bool{}
+	seenPlanIDs := map[string]int{}
+	for _, row := range rows {
+		if isPlaceholder(row.planID) || isPlaceholder(row.planFile) {
+			add("placeholder-row", settings.PlanIndexFile, row.line, "index row still contains template placeholders")
+			continue
+		}
+
+		if previous, ok := seenPlanIDs[row.planID]; ok {
+			add("duplicate-plan-id", settings.PlanIndexFile, row.line, "%s already indexed on line %d", row.planID, previous)
+		} else {
+			seenPlanIDs[row.planID] = row.line
+		}
+
+		planRel := filepath.ToSlash(filepath.Clean(filepath.FromSlash(row.planFile)))
+		if !strings.Contains(planRel, "/") {
+			planRel = strings.TrimSuffix(settings.PlanDirectory, "/") + "/" + planRel
+		}
+		indexed[planRel] = true
+		planPath := filepath.Join(absRoot, filepath.FromSlash(planRel))
+		base := filepath.Base(planRel)
+		if !namingRegex.MatchString(base) {
+			add("plan-file-pattern", settings.PlanIndexFile, row.line, "plan file %s does not match %s", base, settings.PlanStoryFilePattern)
+		} else if row.planID != "" && !strings.HasPrefix(base, row.planID+"-") {
+			add("plan-id-mismatch", settings.PlanIndexFile, row.line, "plan file %s does not carry plan ID %s", base, row.planID)
+		}
+		planBody, planErr := os.ReadFile(planPath)
+		if planErr != nil {
+			add("plan-file-missing", settings.PlanIndexFile, row.line, "plan file %s does not exist", planRel)
+		}
+
+		if !validStatuses[strings.ToUpper(row.status)] {
+			add("invalid-status", settings.PlanIndexFile, row.line, "status %q is not one of %s", row.status, strings.Join(sortedSet(validStatuses), ", "))
+		}
+
+		switch repo := strings.TrimSpace(row.targetRepo); {
+		case repo == "" || isPlaceholder(repo):
+			add("target-repo-missing", settings.PlanIndexFile, row.line, "%s has no target repo", row.planID)
+		case repo == "." || strings.EqualFold(repo, "root"):
+		default:
+			repoRel := repo
+			if !strings.HasPrefix(repoRel, "repos/") {
+				repoRel = "repos/" + repoRel
+			}
+			if info, statErr := os.Stat(filepath.Join(absRoot, filepath.FromSlash(repoRel))); statErr != nil || !info.IsDir() {
+				add("target-repo-missing", settings.PlanIndexFile, row.line, "target repo %s does not exist", repoRel)
+			}
+		}
+
+		reqs := append([]string{}, row.reqIDs...)
+		if planErr == nil {
+			reqs = append(reqs, reqIDPattern.FindAllString(string(planBody), -1)...)
+		}
+		if settings.TraceabilityRequired && len(row.reqIDs) == 0 {
+			add("traceability-required", settings.PlanIndexFile, row.line, "%s has no REQ ID (plan_traceability_required)", row.planID)
+		}
+		if reqErr == nil {
+			for _, reqID := range uniqueStrings(reqs) {
+				if !knownReqs[reqID] {
+					add("unknown-req-id", settings.PlanIndexFile, row.line, "%s references %s, which is not defined in %s", row.planID, reqID, filepath.ToSlash(strings.TrimSpace(*requirementsFile)))
+				}
+			}
+		}
+	}
+
+	planDir := filepath.Join(absRoot, filepath.FromSlash(settings.PlanDirectory))
+	planFiles := []string{}
+	if entries, readErr := os.ReadDir(planDir); readErr == nil {
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasPrefix(entry.Name(), "PLAN-") || !strings.HasSuffix(entry.Name(), ".md") {
+				continue
+			}
+			rel := strings.TrimSuffix(settings.PlanDirectory, "/") + "/" + entry.Name()
+			planFiles = append(planFiles, rel)
+			if !indexed[rel] {
+				add("plan-not-indexed", rel, 0, "plan file is not listed in %s", settings.PlanIndexFile)
+			}
+		}
+	}
+
+	details := map[string]any{
+		"plan_settings": settings,
+		"index_rows":    len(rows),
+		"plan_files":    planFiles,
+	}
+	if len(findings) > 0 {
+		details["findings"] = findings
+		printBlocked([]string{"planning lint findings"}, details)
+		return
+	}
+
+	details["status"] = "PASS"
+	payload, _ := json.Marshal(details)
+	fmt.Println(string(payload))
+}
+
+// loadPlanSettings reads the plan_* keys from the resolved profile, falling
+// back to the documented defaults when no profile is installed.
+func loadPlanSettings(targetRoot, explicit string) (planSettings, error) {
+	settings := planSettings{
+		PlanDirectory:        "docs/plans",
+		PlanIndexFile:        "docs/plans/index.md",
+		PlanStoryFilePattern: "PLAN-{id}-{slug}.md",
+	}
+	profilePath := resolveProfile(targetRoot, explicit)
+	if profilePath == "" {
+		if explicit != "" {
+			return settings, fmt.Errorf("profile not found: %s", explicit)
+		}
+		return settings, nil
+	}
+	values, err := readTopLevelScalars(profilePath)
+	if err != nil {
+		return settings, err
+	}
+	settings.ProfileFile = filepath.ToSlash(profilePath)
+	if value := strings.TrimSpace(values["plan_directory"]); value != "" {
+		settings.PlanDirectory = strings.TrimSuffix(filepath.ToSlash(value), "/")
+	}
+	if value := strings.TrimSpace(values["plan_index_file"]); value != "" {
+		settings.PlanIndexFile = filepath.ToSlash(value)
+	}
+	if value := strings.TrimSpace(values["plan_story_file_pattern"]); value != "" {
+		settings.PlanStoryFilePattern = value
+	}
+	settings.TraceabilityRequired = strings.EqualFold(strings.TrimSpace(values["plan_traceability_required"]), "true")
+	return settings, nil
+}
+
+func resolveProfile(targetRoot, explicit string) string {
+	if explicit != "" {
+		if exists(explicit) {
+			return explicit
+		}
+		joined := filepath.Join(targetRoot, explicit)
+		if exists(joined) {
+			return joined
+		}
+		return ""
+	}
+
+	candidates := []string{
+		filepath.Join(targetRoot, "docs", "source", "02-architecture", "planning-behavior-profile.yaml"),
+		filepath.Join(targetRoot, "docs", "source", "DemoArchitectureDocs", "planning-behavior-profile.yaml"),
+		filepath.Join(targetRoot, ".github", "skills", "local-mcp-setup", "corporate-docs", "planning-behavior-profile.yaml"),
+	}
+	for _, candidate := range candidates {
+		if exists(candidate) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+func exists(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return !info.IsDir()
+}
+
+func readTopLevelScalars(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	out := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+		idx := strings.Index(trimmed, ":")
+		if idx <= 0 {
+			continue
+		}
+		key := strings.TrimSpace(trimmed[:idx])
+		value := strings.TrimSpace(trimmed[idx+1:])
+		if value != "" {
+			out[key] = strings.Trim(value, "\"'")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// parseIndex reads the first markdown table in the plan index. Columns are
+// located by header (REQ ID, PLAN ID, Plan File, Target Repo, Owner, Status).
+func parseIndex(path string) ([]indexRow, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	rows := []indexRow{}
+	var header []string
+	for i, raw := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		line := strings.TrimSpace(raw)
+		if !strings.HasPrefix(line, "|") {
+			if header != nil && len(rows) > 0 {
+				break
+			}
+			continue
+		}
+		cells := parseTableRow(line)
+		if header == nil {
+			header = cells
+			continue
+		}
+		if strings.Contains(line, "---") {
+			continue
+		}
+		row := indexRow{
+			line:       i + 1,
+			planID:     cellAt(cells, columnIndex(header, "plan id", "plan_id")),
+			planFile:   strings.Trim(cellAt(cells, columnIndex(header, "plan file", "plan_file", "file")), "`"),
+			targetRepo: strings.Trim(cellAt(cells, columnIndex(header, "target repo", "target_repo", "repo")), "`"),
+			owner:      cellAt(cells, columnIndex(header, "owner")),
+			status:     cellAt(cells, columnIndex(header, "status")),
+		}
+		row.reqIDs = reqIDPattern.FindAllString(cellAt(cells, columnIndex(header, "req id", "req_id", "req ids", "requirements")), -1)
+		if match := planIDPattern.FindString(row.planID); match != "" {
+			row.planID = match
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func parseTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	line = strings.TrimSuffix(line, "|")
+	parts := strings.Split(line, "|")
+	cells := make([]string, 0, len(parts))
+	for _, part := range parts {
+		cells = append(cells, strings.TrimSpace(part))
+	}
+	return cells
+}
+
+func columnIndex(header []string, names ...string) int {
+	for i, cell := range header {
+		cell = strings.ToLower(strings.TrimSpace(cell))
+		for _, name := range names {
+			if cell == name {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func cellAt(cells []string, index int) string {
+	if index < 0 || index >= len(cells) {
+		return ""
+	}
+	return strings.TrimSpace(cells[index])
+}
+
+func isPlaceholder(value string) bool {
+	return strings.Contains(value, "<") && strings.Contains(value, ">")
+}
+
+func collectIDs(path string, pattern *regexp.Regexp) (map[string]bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ids := map[string]bool{}
+	for _, id := range pattern.FindAllString(string(data), -1) {
+		ids[id] = true
+	}
+	return ids, nil
+}
+
+// namingToRegex turns PLAN-{id}-{slug}.md into an anchored regex; `{id}` is
+// an alphanumeric ID and `{slug}` a kebab-case slug.
+func namingToRegex(pattern string) string {
+	quoted := regexp.QuoteMeta(pattern)
+	quoted = strings.ReplaceAll(quoted, regexp.QuoteMeta("{id}"), `[A-Za-z0-9]+`)
+	quoted = strings.ReplaceAll(quoted, regexp.QuoteMeta("{slug}"), `[a-z0-9]+(?:-[a-z0-9]+)*`)
+	return "^" + quoted + "$"
+}
+
+func uniqueStrings(values []string) []string {
+	seen := map[string]bool{}
+	out := []string{}
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			out = append(out, value)
+		}
+	}
+	return out
+}
+
+func sortedSet(values map[string]bool) []string {
+	out := make([]string, 0, len(values))
+	for value := range values {
+		out = append(out, value)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func printBlocked(issues []string, details map[string]any) {
+	payload := map[string]any{
+		"status": "BLOCKED",
+		"issues": issues,
+	}
+	for k, v := range details {
+		payload[k] = v
+	}
+	data, _ := json.Marshal(payload)
+	fmt.Println(string(data))
+}
@@ -2,18 +2,28 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
 func main() {
 	targetRoot := flag.String("target-root", "", "target repository root (defaults to cwd)")
 	file := flag.String("file", "", "optional severity classification markdown file path")
+	policyFile := flag.String("policy-file", "", "optional severity taxonomy policy YAML (defaults to docs/tooling/release-severity-policy.yaml, then the planning profile release_severity section)")
+	profileFile := flag.String("profile-file", "", "optional explicit planning behavior profile path")
+	asOf := flag.String("as-of", "", "date (YYYY-MM-DD) used to detect past target dates (defaults to today UTC)")
+	enforceVerdict := flag.Bool("enforce-verdict", false, "report BLOCKED when the release readiness verdict is NO-GO")
+	openapiLint := flag.String("openapi-lint", "", "openapi_lint command source used to decode YAML policy files (defaults to .github/skills/local-mcp-setup/cmd/openapi_lint/main.go under the working directory, then the target root)")
+	ownersFile := flag.String("owners-file", "OWNERS.md", "owner roster (OWNERS.md or a markdown table with Name/Role/Team columns); a missing default file skips owner validation")
 	flag.Parse()
 
 	root := strings.TrimSpace(*targetRoot)
@@ -37,14 +47,34 @@ func main() {
 		return
 	}
 
-	findings, ownership, err := parseSeveritySections(path)
+	asOfDate := time.Now().UTC().Truncate(24 * time.Hour)
+	if strings.TrimSpace(*asOf) != "" {
+		parsed, parseErr := time.Parse("2006-01-02", strings.TrimSpace(*asOf))
+		if parseErr != nil {
+			printBlocked("", []string{"--as-of must be YYYY-MM-DD"}, nil)
+			return
+		}
+		asOfDate = parsed
+	}
+
+	taxonomy, err := loadTaxonomy(absRoot, strings.TrimSpace(*policyFile), strings.TrimSpace(*profileFile), openapiLintCommand(absRoot, strings.TrimSpace(*openapiLint)))
+	if err != nil {
+		printBlocked(path, []string{fmt.Sprintf("failed to load severity taxonomy: %v", err)}, nil)
+		return
+	}
+
+	findings, ownership, ownershipRows, err := parseSeveritySections(path)
 	if err != nil {
-		printBlocked(path, []string{fmt.Sprintf("failed to parse severity file: %v", err)}, nil)
+		printBlocked(path, []string{fmt.Sprintf("failed to parse severity file: %v", err)}, map[string]any{"severity_taxonomy": taxonomy})
 		return
 	}
 
-	blockerRefs := requiredBlockers(findings)
+	blockerRefs := requiredBlockers(findings, taxonomy)
+	diagnostics := lintStructure(findings, ownershipRows, taxonomy)
 	issues := []string{}
+	if len(diagnostics) > 0 {
+		issues = append(issues, "severity classification has duplicate, orphan or unknown-severity rows")
+	}
 	missing := []string{}
 	for _, blockerID := range blockerRefs {
 		row, ok := ownership[blockerID]
@@ -52,7 +82,7 @@ func main() {
 			missing = append(missing, blockerID)
 			continue
 		}
-		if !rowComplete(row) {
+		if !rowComplete(row.values) {
 			issues = append(issues, fmt.Sprintf("blocker ownership incomplete for %s", blockerID))
 		}
 	}
@@ -61,10 +91,46 @@ func main() {
 		issues = append(issues, "missing blocker ownership rows")
 	}
 
+	rosterPath := resolveRoster(absRoot, strings.TrimSpace(*ownersFile))
+	rosterSkipped := ""
+	ownerDiagnostics := []lintDiagnostic{}
+	if rosterPath != "" {
+		roster, named, rosterErr := parseRoster(rosterPath)
+		if rosterErr != nil {
+			printBlocked(path, []string{fmt.Sprintf("failed to read owner roster: %v", rosterErr)}, map[string]any{"severity_taxonomy": taxonomy})
+			return
+		}
+		// A bullet-only OWNERS.md names a team and roles, not the people who
+		// own blockers; validating named owners against it would flag them all.
+		if named || flagSet("owners-file") {
+			ownerDiagnostics = validateOwners(findings, ownership, blockerRefs, roster, taxonomy)
+		} else {
+			rosterSkipped = fmt.Sprintf("%s has no Name/Role/Team table; owner validation skipped", filepath.ToSlash(rosterPath))
+			rosterPath = ""
+		}
+	} else if flagSet("owners-file") {
+		printBlocked(path, []string{fmt.Sprintf("owner roster not found: %s", strings.TrimSpace(*ownersFile))}, map[string]any{"severity_taxonomy": taxonomy})
+		return
+	}
+	if len(ownerDiagnostics) > 0 {
+		issues = append(issues, "blocker owners failed roster validation")
+		diagnostics = append(diagnostics, ownerDiagnostics...)
+	}
+
+	readiness := evaluateReadiness(findings, ownership, taxonomy, asOfDate)
+	if *enforceVerdict && readiness.Verdict == "NO-GO" {
+		issues = append(issues, "release readiness verdict is NO-GO")
+	}
+
 	if len(issues) > 0 {
 		printBlocked(path, issues, map[string]any{
 			"required_blockers": blockerRefs,
 			"missing_blockers":  missing,
+			"severity_taxonomy": taxonomy,
+			"release_readiness": readiness,
+			"diagnostics":       diagnostics,
+			"owner_roster":      filepath.ToSlash(rosterPath),
+			"owner_roster_note": rosterSkipped,
 		})
 		return
 	}
@@ -73,13 +139,55 @@ func main() {
 		"status":            "PASS",
 		"severity_file":     filepath.ToSlash(path),
 		"required_blockers": blockerRefs,
+		"severity_taxonomy": taxonomy,
+		"release_readiness": readiness,
+		"owner_roster":      filepath.ToSlash(rosterPath),
+		"owner_roster_note": rosterSkipped,
 	})
 	fmt.Println(string(payload))
 }
 
 type finding struct {
+	id        string
 	severity  string
 	blockerID string
+	status    string
+	line      int
+}
+
+type ownershipRow struct {
+	blockerID  string
+	owner      string
+	values     []string
+	status     string
+	targetDate string
+	line       int
+}
+
+type lintDiagnostic struct {
+	Kind    string `json:"kind"`
+	ID      string `json:"id"`
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+type releaseReadiness struct {
+	Verdict          string         `json:"verdict"`
+	AsOf             string         `json:"as_of"`
+	OpenBySeverity   map[string]int `json:"open_by_severity"`
+	BlockingFindings []string       `json:"blocking_findings"`
+	OverdueBlockers  []string       `json:"overdue_blockers"`
+	Conditions       []string       `json:"conditions"`
+}
+
+var closedStatuses = map[string]bool{
+	"closed":    true,
+	"resolved":  true,
+	"done":      true,
+	"fixed":     true,
+	"mitigated": true,
+	"verified":  true,
+	"waived":    true,
 }
 
 func resolveSeverityFile(root, explicit string) (string, error) {
@@ -118,19 +226,28 @@ func exists(path string) bool {
 	return !info.IsDir()
 }
 
-func parseSeveritySections(path string) ([]finding, map[string][]string, error) {
+// parseSeveritySections reads the findings and blocker ownership tables.
+// Optional status and target date columns are located from the header row.
+// The ownership map keeps the first row per blocker; rows lists every
+// ownership row in file order so duplicates can be reported.
+func parseSeveritySections(path string) ([]finding, map[string]ownershipRow, []ownershipRow, error) {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 	defer f.Close()
 
 	section := ""
 	findings := []finding{}
-	ownership := map[string][]string{}
+	ownership := map[string]ownershipRow{}
+	rows := []ownershipRow{}
+	findingStatusCol := -1
+	ownerCol, ownerStatusCol, targetDateCol := 1, -1, -1
 
 	scanner := bufio.NewScanner(f)
+	lineNo := 0
 	for scanner.Scan() {
+		lineNo++
 		line := strings.TrimSpace(scanner.Text())
 		lower := strings.ToLower(line)
 		switch lower {
@@ -149,28 +266,184 @@ func parseSeveritySections(path string) ([]finding, map[string][]string, error)
 		}
 		cells := parseTableRow(line)
 		if section == "findings" {
-			if len(cells) < 5 || strings.EqualFold(cells[0], "Finding ID") {
+			if len(cells) < 5 {
 				continue
 			}
-			findings = append(findings, finding{severity: strings.TrimSpace(cells[2]), blockerID: strings.TrimSpace(cells[4])})
+			if strings.EqualFold(cells[0], "Finding ID") {
+				findingStatusCol = columnIndex(cells, "status")
+				continue
+			}
+			findings = append(findings, finding{
+				id:        strings.TrimSpace(cells[0]),
+				severity:  strings.TrimSpace(cells[2]),
+				blockerID: strings.TrimSpace(cells[4]),
+				status:    cellAt(cells, findingStatusCol),
+				line:      lineNo,
+			})
 		}
 		if section == "ownership" {
-			if len(cells) < 5 || strings.EqualFold(cells[0], "blocker_id") {
+			if len(cells) < 5 {
 				continue
 			}
-			blockerID := strings.TrimSpace(cells[0])
-			ownership[blockerID] = []string{
-				strings.TrimSpace(cells[1]),
-				strings.TrimSpace(cells[2]),
-				strings.TrimSpace(cells[3]),
-				strings.TrimSpace(cells[4]),
+			if strings.EqualFold(cells[0], "blocker_id") {
+				if col := columnIndex(cells, "owner", "blocker_owner", "owner_name"); col >= 0 {
+					ownerCol = col
+				}
+				ownerStatusCol = columnIndex(cells, "status")
+				targetDateCol = columnIndex(cells, "target_date", "target date", "due_date", "due date")
+				continue
+			}
+			row := ownershipRow{
+				blockerID: strings.TrimSpace(cells[0]),
+				owner:     cellAt(cells, ownerCol),
+				values: []string{
+					strings.TrimSpace(cells[1]),
+					strings.TrimSpace(cells[2]),
+					strings.TrimSpace(cells[3]),
+					strings.TrimSpace(cells[4]),
+				},
+				status:     cellAt(cells, ownerStatusCol),
+				targetDate: cellAt(cells, targetDateCol),
+				line:       lineNo,
+			}
+			rows = append(rows, row)
+			if _, seen := ownership[row.blockerID]; !seen {
+				ownership[row.blockerID] = row
 			}
 		}
 	}
 	if err := scanner.Err(); err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
+	}
+	return findings, ownership, rows, nil
+}
+
+// lintStructure reports duplicate finding and blocker IDs, ownership rows no
+// finding references, and severities outside the taxonomy, with source lines.
+func lintStructure(findings []finding, rows []ownershipRow, taxonomy severityTaxonomy) []lintDiagnostic {
+	diagnostics := []lintDiagnostic{}
+	findingLines := map[string]int{}
+	referenced := map[string]bool{}
+	for _, row := range findings {
+		if row.blockerID != "" && row.blockerID != "-" {
+			referenced[row.blockerID] = true
+		}
+		if _, ok := taxonomy.lookup(row.severity); !ok && !taxonomy.requiresOwner(row.severity) {
+			diagnostics = append(diagnostics, lintDiagnostic{Kind: "unknown-severity", ID: row.id, Line: row.line, Message: fmt.Sprintf("finding %s has severity %q outside the taxonomy", row.id, row.severity)})
+		}
+		if row.id == "" {
+			continue
+		}
+		if first, ok := findingLines[row.id]; ok {
+			diagnostics = append(diagnostics, lintDiagnostic{Kind: "duplicate-finding-id", ID: row.id, Line: row.line, Message: fmt.Sprintf("finding %s already defined on line %d", row.id, first)})
+			continue
+		}
+		findingLines[row.id] = row.line
+	}
+
+	blockerLines := map[string]int{}
+	for _, row := range rows {
+		if first, ok := blockerLines[row.blockerID]; ok {
+			diagnostics = append(diagnostics, lintDiagnostic{Kind: "duplicate-blocker-id", ID: row.blockerID, Line: row.line, Message: fmt.Sprintf("blocker %s already has an ownership row on line %d", row.blockerID, first)})
+			continue
+		}
+		blockerLines[row.blockerID] = row.line
+		if !referenced[row.blockerID] {
+			diagnostics = append(diagnostics, lintDiagnostic{Kind: "orphan-ownership-row", ID: row.blockerID, Line: row.line, Message: fmt.Sprintf("blocker %s is not referenced by any finding", row.blockerID)})
+		}
+	}
+	sort.SliceStable(diagnostics, func(i, j int) bool { return diagnostics[i].Line < diagnostics[j].Line })
+	return diagnostics
+}
+
+func columnIndex(header []string, names ...string) int {
+	for i, cell := range header {
+		value := strings.ToLower(strings.TrimSpace(cell))
+		for _, name := range names {
+			if value == name {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func cellAt(cells []string, index int) string {
+	if index < 0 || index >= len(cells) {
+		return ""
+	}
+	return strings.TrimSpace(cells[index])
+}
+
+func isClosed(status string) bool {
+	return closedStatuses[strings.ToLower(strings.TrimSpace(status))]
+}
+
+// evaluateReadiness counts open findings per severity level. A finding is open
+// unless its own status or its blocker's status is closed. Exceeding a level's
+// max_open_for_release is NO-GO; remaining open blockers or past/invalid
+// target dates make the verdict CONDITIONAL.
+func evaluateReadiness(findings []finding, ownership map[string]ownershipRow, taxonomy severityTaxonomy, asOf time.Time) releaseReadiness {
+	readiness := releaseReadiness{
+		Verdict:          "GO",
+		AsOf:             asOf.Format("2006-01-02"),
+		OpenBySeverity:   map[string]int{},
+		BlockingFindings: []string{},
+		OverdueBlockers:  []string{},
+		Conditions:       []string{},
 	}
-	return findings, ownership, nil
+
+	openByLevel := map[string][]string{}
+	overdue := map[string]bool{}
+	openBlockerFindings := 0
+	for _, row := range findings {
+		blocker, hasBlocker := ownership[row.blockerID]
+		if isClosed(row.status) || (hasBlocker && isClosed(blocker.status)) {
+			continue
+		}
+		if level, ok := taxonomy.lookup(row.severity); ok {
+			openByLevel[level.Name] = append(openByLevel[level.Name], row.id)
+		}
+		if !taxonomy.requiresOwner(row.severity) {
+			continue
+		}
+		openBlockerFindings++
+		if !hasBlocker || blocker.targetDate == "" || overdue[row.blockerID] {
+			continue
+		}
+		target, err := time.Parse("2006-01-02", blocker.targetDate)
+		if err != nil {
+			readiness.Conditions = append(readiness.Conditions, fmt.Sprintf("blocker %s has unparseable target date %q", row.blockerID, blocker.targetDate))
+			continue
+		}
+		if target.Before(asOf) {
+			overdue[row.blockerID] = true
+			readiness.OverdueBlockers = append(readiness.OverdueBlockers, row.blockerID)
+		}
+	}
+
+	for _, level := range taxonomy.Levels {
+		open := openByLevel[level.Name]
+		readiness.OpenBySeverity[level.Name] = len(open)
+		if level.MaxOpenForRelease >= 0 && len(open) > level.MaxOpenForRelease {
+			readiness.Verdict = "NO-GO"
+			readiness.BlockingFindings = append(readiness.BlockingFindings, open...)
+		}
+	}
+	sort.Strings(readiness.OverdueBlockers)
+
+	if readiness.Verdict == "GO" {
+		if openBlockerFindings > 0 {
+			readiness.Conditions = append(readiness.Conditions, fmt.Sprintf("%d open finding(s) with blocker-requiring severity within release limits", openBlockerFindings))
+		}
+		if len(readiness.OverdueBlockers) > 0 {
+			readiness.Conditions = append(readiness.Conditions, "open blockers past their target date")
+		}
+		if len(readiness.Conditions) > 0 {
+			readiness.Verdict = "CONDITIONAL"
+		}
+	}
+	return readiness
 }
 
 func parseTableRow(line string) []string {
@@ -185,16 +458,15 @@ func parseTableRow(line string) []string {
 	return out
 }
 
-func requiredBlockers(findings []finding) []string {
+func requiredBlockers(findings []finding, taxonomy severityTaxonomy) []string {
 	set := map[string]bool{}
 	out := []string{}
 	for _, row := range findings {
-		sev := strings.ToLower(strings.TrimSpace(row.severity))
 		blockerID := strings.TrimSpace(row.blockerID)
 		if blockerID == "" || blockerID == "-" {
 			continue
 		}
-		if sev == "sev-1" || sev == "sev-2" || strings.Contains(sev, "blocked") {
+		if taxonomy.requiresOwner(row.severity) {
 			if !set[blockerID] {
 				set[blockerID] = true
 				out = append(out, blockerID)
@@ -205,6 +477,325 @@ func requiredBlockers(findings []finding) []string {
 	return out
 }
 
+type severityLevel struct {
+	Name                 string   `json:"name"`
+	Rank                 int      `json:"rank"`
+	Aliases              []string `json:"aliases,omitempty"`
+	RequiresBlockerOwner bool     `json:"requires_blocker_owner"`
+	MaxOpenForRelease    int      `json:"max_open_for_release"`
+}
+
+// severityTaxonomy describes the severity scale used by the findings table.
+// MaxOpenForRelease of -1 means the level does not limit release.
+type severityTaxonomy struct {
+	Source              string          `json:"source"`
+	Levels              []severityLevel `json:"levels"`
+	BlockerKeywords     []string        `json:"blocker_keywords"`
+	BlockerOwnerRoles   []string        `json:"blocker_owner_roles"`
+	MaxTopLevelPerOwner int             `json:"max_top_level_blockers_per_owner"`
+}
+
+func defaultTaxonomy() severityTaxonomy {
+	return severityTaxonomy{
+		Source: "built-in defaults",
+		Levels: []severityLevel{
+			{Name: "sev-1", Rank: 1, RequiresBlockerOwner: true, MaxOpenForRelease: 0},
+			{Name: "sev-2", Rank: 2, RequiresBlockerOwner: true, MaxOpenForRelease: 0},
+			{Name: "sev-3", Rank: 3, MaxOpenForRelease: -1},
+			{Name: "sev-4", Rank: 4, MaxOpenForRelease: -1},
+		},
+		BlockerKeywords:     []string{"blocked"},
+		BlockerOwnerRoles:   []string{},
+		MaxTopLevelPerOwner: 3,
+	}
+}
+
+func (t severityTaxonomy) lookup(severity string) (severityLevel, bool) {
+	value := strings.ToLower(strings.TrimSpace(severity))
+	for _, level := range t.Levels {
+		if strings.ToLower(level.Name) == value {
+			return level, true
+		}
+		for _, alias := range level.Aliases {
+			if strings.ToLower(alias) == value {
+				return level, true
+			}
+		}
+	}
+	return severityLevel{}, false
+}
+
+func (t severityTaxonomy) requiresOwner(severity string) bool {
+	if level, ok := t.lookup(severity); ok && level.RequiresBlockerOwner {
+		return true
+	}
+	value := strings.ToLower(strings.TrimSpace(severity))
+	for _, keyword := range t.BlockerKeywords {
+		if keyword != "" && strings.Contains(value, strings.ToLower(keyword)) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadTaxonomy resolves the severity scale from an explicit policy file, the
+// default policy path, or the planning profile's release_severity section,
+// falling back to the built-in sev-1..sev-4 scale.
+func loadTaxonomy(root, policyFile, profileFile, lintCommand string) (severityTaxonomy, error) {
+	if policyFile != "" {
+		path := policyFile
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(root, filepath.FromSlash(path))
+		}
+		taxonomy, ok, err := taxonomyFromFile(path, lintCommand)
+		if err != nil {
+			return severityTaxonomy{}, err
+		}
+		if !ok {
+			return severityTaxonomy{}, fmt.Errorf("no severity levels defined in %s", filepath.ToSlash(path))
+		}
+		return taxonomy, nil
+	}
+
+	candidates := []string{filepath.Join(root, "docs", "tooling", "release-severity-policy.yaml")}
+	if profileFile != "" {
+		if filepath.IsAbs(profileFile) {
+			candidates = append(candidates, profileFile)
+		} else {
+			candidates = append(candidates, filepath.Join(root, filepath.FromSlash(profileFile)))
+		}
+	} else {
+		candidates = append(candidates,
+			filepath.Join(root, "docs", "source", "02-architecture", "planning-behavior-profile.yaml"),
+			filepath.Join(root, "docs", "source", "DemoArchitectureDocs", "planning-behavior-profile.yaml"),
+			filepath.Join(root, ".github", "skills", "local-mcp-setup", "corporate-docs", "planning-behavior-profile.yaml"),
+		)
+	}
+	for _, candidate := range candidates {
+		if !exists(candidate) {
+			continue
+		}
+		taxonomy, ok, err := taxonomyFromFile(candidate, lintCommand)
+		if err != nil {
+			return severityTaxonomy{}, err
+		}
+		if ok {
+			return taxonomy, nil
+		}
+	}
+	return defaultTaxonomy(), nil
+}
+
+func taxonomyFromFile(path, lintCommand string) (severityTaxonomy, bool, error) {
+	values, err := readYAMLPaths(path, lintCommand)
+	if err != nil {
+		return severityTaxonomy{}, false, fmt.Errorf("%s: %v", filepath.ToSlash(path), err)
+	}
+	section := ""
+	if yamlItems(values, "release_severity.levels") > 0 {
+		section = "release_severity."
+	}
+	count := yamlItems(values, section+"levels")
+	if count == 0 {
+		return severityTaxonomy{}, false, nil
+	}
+
+	taxonomy := severityTaxonomy{Source: filepath.ToSlash(path), BlockerKeywords: []string{}, BlockerOwnerRoles: []string{}, MaxTopLevelPerOwner: 3}
+	taxonomy.BlockerKeywords = yamlList(values, section+"blocker_keywords")
+	taxonomy.BlockerOwnerRoles = yamlList(values, section+"blocker_owner_roles")
+	if maxPerOwner, ok := intField(values, section+"max_top_level_blockers_per_owner"); ok {
+		taxonomy.MaxTopLevelPerOwner = maxPerOwner
+	}
+	for i := 0; i < count; i++ {
+		prefix := section + "levels." + strconv.Itoa(i)
+		if _, scalar := values[prefix]; scalar {
+			return severityTaxonomy{}, false, fmt.Errorf("%s: severity level %d is not a mapping", filepath.ToSlash(path), i+1)
+		}
+		level := severityLevel{
+			Name:                 strings.TrimSpace(values[prefix+".name"]),
+			Rank:                 i + 1,
+			RequiresBlockerOwner: values[prefix+".requires_blocker_owner"] == "true",
+			MaxOpenForRelease:    -1,
+		}
+		if level.Name == "" {
+			return severityTaxonomy{}, false, fmt.Errorf("%s: severity level %d has no name", filepath.ToSlash(path), i+1)
+		}
+		if rank, ok := intField(values, prefix+".rank"); ok {
+			level.Rank = rank
+		}
+		if maxOpen, ok := intField(values, prefix+".max_open_for_release"); ok {
+			level.MaxOpenForRelease = maxOpen
+		}
+		if aliases := yamlList(values, prefix+".aliases"); len(aliases) > 0 {
+			level.Aliases = aliases
+		}
+		taxonomy.Levels = append(taxonomy.Levels, level)
+	}
+	sort.SliceStable(taxonomy.Levels, func(i, j int) bool { return taxonomy.Levels[i].Rank < taxonomy.Levels[j].Rank })
+	return taxonomy, true, nil
+}
+
+func intField(values map[string]string, key string) (int, bool) {
+	raw, ok := values[key]
+	if !ok {
+		return 0, false
+	}
+	value, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+type rosterEntry struct {
+	Name string
+	Role string
+	Team string
+}
+
+func resolveRoster(root, value string) string {
+	if value == "" {
+		return ""
+	}
+	if !filepath.IsAbs(value) {
+		value = filepath.Join(root, filepath.FromSlash(value))
+	}
+	if !exists(value) {
+		return ""
+	}
+	return value
+}
+
+// parseRoster reads owners from markdown tables with a Name column (plus
+// optional Role and Team columns), `- Team: <name>` bullets, and bullets under
+// an "Accountable roles" heading, which are treated as role-named owners.
+// named reports whether the file had a Name (or Owner) table at all.
+func parseRoster(path string) ([]rosterEntry, bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false, err
+	}
+	named := false
+	entries := []rosterEntry{}
+	heading := ""
+	nameCol, roleCol, teamCol := -1, -1, -1
+	for _, raw := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		line := strings.TrimSpace(raw)
+		if strings.HasPrefix(line, "#") {
+			heading = strings.ToLower(strings.TrimSpace(strings.TrimLeft(line, "#")))
+			nameCol = -1
+			continue
+		}
+		if strings.HasPrefix(line, "|") {
+			if strings.Contains(line, "---") {
+				continue
+			}
+			cells := parseTableRow(line)
+			if col := columnIndex(cells, "name", "owner"); col >= 0 && nameCol < 0 {
+				nameCol = col
+				named = true
+				roleCol = columnIndex(cells, "role")
+				teamCol = columnIndex(cells, "team")
+				continue
+			}
+			if nameCol >= 0 && cellAt(cells, nameCol) != "" {
+				entries = append(entries, rosterEntry{Name: cellAt(cells, nameCol), Role: cellAt(cells, roleCol), Team: cellAt(cells, teamCol)})
+			}
+			continue
+		}
+		nameCol = -1
+		if !strings.HasPrefix(line, "- ") {
+			continue
+		}
+		item := strings.TrimSpace(strings.TrimPrefix(line, "- "))
+		if key, value, ok := strings.Cut(item, ":"); ok && strings.EqualFold(strings.TrimSpace(key), "team") {
+			team := strings.TrimSpace(value)
+			entries = append(entries, rosterEntry{Name: team, Team: team})
+			continue
+		}
+		if strings.Contains(heading, "accountable roles") {
+			entries = append(entries, rosterEntry{Name: item, Role: item})
+		}
+	}
+	return entries, named, nil
+}
+
+func normalizeOwner(value string) string {
+	return strings.ToLower(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(value), "@")))
+}
+
+// validateOwners checks owners of required blockers against the roster: the
+// owner must be known, hold a permitted role when blocker_owner_roles is set,
+// and not hold more open top-level blockers than the policy allows.
+func validateOwners(findings []finding, ownership map[string]ownershipRow, required []string, roster []rosterEntry, taxonomy severityTaxonomy) []lintDiagnostic {
+	byName := map[string]rosterEntry{}
+	for _, entry := range roster {
+		byName[normalizeOwner(entry.Name)] = entry
+	}
+	permitted := map[string]bool{}
+	for _, role := range taxonomy.BlockerOwnerRoles {
+		permitted[strings.ToLower(strings.TrimSpace(role))] = true
+	}
+
+	diagnostics := []lintDiagnostic{}
+	for _, blockerID := range required {
+		row, ok := ownership[blockerID]
+		if !ok || !rowComplete(row.values) {
+			continue
+		}
+		entry, known := byName[normalizeOwner(row.owner)]
+		if !known {
+			diagnostics = append(diagnostics, lintDiagnostic{Kind: "unknown-owner", ID: blockerID, Line: row.line, Message: fmt.Sprintf("owner %q of blocker %s is not in the owner roster", row.owner, blockerID)})
+			continue
+		}
+		if len(permitted) > 0 && !permitted[strings.ToLower(strings.TrimSpace(entry.Role))] {
+			diagnostics = append(diagnostics, lintDiagnostic{Kind: "owner-role-not-permitted", ID: blockerID, Line: row.line, Message: fmt.Sprintf("owner %q (role %q) is not permitted to own release blockers", row.owner, entry.Role)})
+		}
+	}
+
+	if len(taxonomy.Levels) == 0 || taxonomy.MaxTopLevelPerOwner < 0 {
+		return diagnostics
+	}
+	top := taxonomy.Levels[0].Name
+	held := map[string][]string{}
+	counted := map[string]bool{}
+	for _, f := range findings {
+		level, ok := taxonomy.lookup(f.severity)
+		row, hasRow := ownership[f.blockerID]
+		if !ok || level.Name != top || !hasRow || counted[f.blockerID] || isClosed(f.status) || isClosed(row.status) {
+			continue
+		}
+		counted[f.blockerID] = true
+		owner := normalizeOwner(row.owner)
+		held[owner] = append(held[owner], f.blockerID)
+	}
+	owners := make([]string, 0, len(held))
+	for owner := range held {
+		owners = append(owners, owner)
+	}
+	sort.Strings(owners)
+	for _, owner := range owners {
+		blockers := held[owner]
+		if len(blockers) <= taxonomy.MaxTopLevelPerOwner {
+			continue
+		}
+		sort.Strings(blockers)
+		row := ownership[blockers[len(blockers)-1]]
+		diagnostics = append(diagnostics, lintDiagnostic{Kind: "owner-overloaded", ID: row.owner, Line: row.line, Message: fmt.Sprintf("owner %q holds %d open %s blockers (%s); policy allows %d", row.owner, len(blockers), top, strings.Join(blockers, ", "), taxonomy.MaxTopLevelPerOwner)})
+	}
+	return diagnostics
+}
+
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
 func rowComplete(values []string) bool {
 	if len(values) < 4 {
 		return false
@@ -232,3 +823,113 @@ func printBlocked(path string, issues []string, details map[string]any) {
 	data, _ := json.Marshal(payload)
 	fmt.Println(string(data))
 }
+
+// openapiLintCommand locates the openapi_lint source. Its --decode mode is the
+// repo's YAML reader, so severity policies and
+// planning profiles get full YAML without a second parser.
+func openapiLintCommand(root, explicit string) string {
+	if explicit != "" {
+		if filepath.IsAbs(explicit) {
+			return explicit
+		}
+		return filepath.Join(root, filepath.FromSlash(explicit))
+	}
+	rel := filepath.Join(".github", "skills", "local-mcp-setup", "cmd", "openapi_lint", "main.go")
+	if cwd, err := os.Getwd(); err == nil && exists(filepath.Join(cwd, rel)) {
+		return filepath.Join(cwd, rel)
+	}
+	return filepath.Join(root, rel)
+}
+
+// readYAMLPaths decodes a YAML file with openapi_lint --decode and flattens the
+// document into dotted paths. Sequence items are numbered from 0, so levels.0.name is
+// the name of the first severity level.
+func readYAMLPaths(path, command string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if !exists(command) {
+		return nil, fmt.Errorf("openapi_lint command not found at %s (set --openapi-lint)", filepath.ToSlash(command))
+	}
+	cmd := exec.Command("go", "run", command, "--decode", filepath.Base(path))
+	cmd.Stdin = bytes.NewReader(data)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	var result struct {
+		Status   string   `json:"status"`
+		Issues   []string `json:"issues"`
+		Document any      `json:"document"`
+	}
+	if jsonErr := json.Unmarshal(output, &result); jsonErr != nil {
+		if err == nil {
+			err = jsonErr
+		}
+		return nil, fmt.Errorf("openapi_lint --decode: %v %s", err, strings.TrimSpace(stderr.String()))
+	}
+	if result.Status != "PASS" {
+		return nil, fmt.Errorf("%s", strings.Join(result.Issues, "; "))
+	}
+	values := map[string]string{}
+	flattenDocument(values, "", result.Document)
+	return values, nil
+}
+
+func flattenDocument(values map[string]string, path string, node any) {
+	child := func(key string) string {
+		if path == "" {
+			return key
+		}
+		return path + "." + key
+	}
+	switch typed := node.(type) {
+	case map[string]any:
+		for key, value := range typed {
+			flattenDocument(values, child(key), value)
+		}
+	case []any:
+		for i, value := range typed {
+			flattenDocument(values, child(strconv.Itoa(i)), value)
+		}
+	case string:
+		values[path] = typed
+	case bool:
+		values[path] = strconv.FormatBool(typed)
+	case float64:
+		values[path] = strconv.FormatFloat(typed, 'f', -1, 64)
+	}
+}
+
+// yamlList returns the scalars of a sequence at key, or the value itself when
+// key holds a single scalar.
+func yamlList(values map[string]string, key string) []string {
+	out := []string{}
+	if value := strings.TrimSpace(values[key]); value != "" {
+		return append(out, value)
+	}
+	for i := 0; ; i++ {
+		value, ok := values[key+"."+strconv.Itoa(i)]
+		if !ok {
+			return out
+		}
+		if value = strings.TrimSpace(value); value != "" {
+			out = append(out, value)
+		}
+	}
+}
+
+// yamlItems counts the mapping items of a block sequence at key.
+func yamlItems(values map[string]string, key string) int {
+	count := 0
+	for path := range values {
+		if !strings.HasPrefix(path, key+".") {
+			continue
+		}
+		index := strings.SplitN(strings.TrimPrefix(path, key+"."), ".", 2)[0]
+		if n, err := strconv.Atoi(index); err == nil && n+1 > count {
+			count = n + 1
+		}
+	}
+	return count
+}
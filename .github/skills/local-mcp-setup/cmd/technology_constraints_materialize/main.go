@@ -0,0 +1,361 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	generatedBegin = "<!-- BEGIN GENERATED: spec-tech-detect -->"
+	generatedEnd   = "<!-- END GENERATED: spec-tech-detect -->"
+)
+
+type techDecisionCandidate struct {
+	Value     string `json:"value"`
+	File      string `json:"file"`
+	Line      int    `json:"line"`
+	MatchedOn string `json:"matched_on"`
+}
+
+type specTechDetect struct {
+	SpecDir           string                           `json:"spec_dir"`
+	Detected          map[string]techDecisionCandidate `json:"detected"`
+	TimestampUTC      string                           `json:"timestamp_utc"`
+	CorporateTechFile string                           `json:"corporate_tech_file"`
+}
+
+type approvedTechBaseline struct {
+	AuthoritySource string `json:"authority_source"`
+	ApprovalOwner   string `json:"approval_owner"`
+	ApprovalStatus  string `json:"approval_status"`
+	Decisions       struct {
+		BackendRuntime    string `json:"backend_runtime"`
+		FrontendFramework string `json:"frontend_framework"`
+		PersistentEngine  string `json:"persistent_engine"`
+		MigrationTool     string `json:"migration_tool"`
+		RedisVersion      string `json:"redis_version"`
+	} `json:"decisions"`
+}
+
+type constraintRow struct {
+	TCID           string `json:"tc_id"`
+	Decision       string `json:"decision"`
+	Value          string `json:"value"`
+	Evidence       string `json:"evidence"`
+	ApprovalStatus string `json:"approval_status"`
+	AdapterID      string `json:"adapter_id,omitempty"`
+}
+
+// decisionOrder fixes TC numbering so IDs stay stable across regenerations.
+var decisionOrder = []string{
+	"backend_runtime",
+	"frontend_framework",
+	"persistent_engine",
+	"cache_engine",
+	"migration_tool",
+}
+
+var coreDecisions = map[string]bool{
+	"backend_runtime":    true,
+	"frontend_framework": true,
+	"persistent_engine":  true,
+}
+
+func main() {
+	targetRoot := flag.String("target-root", "", "target repository root (defaults to cwd)")
+	detectFile := flag.String("spec-tech-file", "docs/tooling/spec-tech-detect.json", "spec-tech-detect output path")
+	corporateTechFile := flag.String("corporate-tech-file", "", "optional corporate approved tech baseline JSON (defaults to the path recorded by spec-tech-detect)")
+	tcFile := flag.String("tc-file", "docs/technology-constraints.md", "technology constraints file to create or update")
+	flag.Parse()
+
+	root := strings.TrimSpace(*targetRoot)
+	if root == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			printBlocked([]string{"unable to determine working directory"}, nil)
+			return
+		}
+		root = cwd
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		printBlocked([]string{"invalid target root"}, nil)
+		return
+	}
+
+	detectPath := resolvePath(absRoot, *detectFile)
+	if detectPath == "" {
+		printBlocked([]string{"--spec-tech-file cannot be empty"}, nil)
+		return
+	}
+	tcPath := resolvePath(absRoot, *tcFile)
+	if tcPath == "" {
+		printBlocked([]string{"--tc-file cannot be empty"}, nil)
+		return
+	}
+
+	detect, err := loadSpecTechDetect(detectPath)
+	if err != nil {
+		printBlocked([]string{fmt.Sprintf("failed to read spec-tech-detect output: %v", err)}, nil)
+		return
+	}
+
+	techFile := strings.TrimSpace(*corporateTechFile)
+	if techFile == "" {
+		techFile = strings.TrimSpace(detect.CorporateTechFile)
+	}
+	if techFile == "" {
+		techFile = filepath.Join(absRoot, ".github", "skills", "local-mcp-setup", "corporate-approved-tech.json")
+	}
+	techFile = resolvePath(absRoot, techFile)
+	baseline, baselineError := loadApprovedTechBaseline(techFile)
+
+	rows := buildConstraintRows(detect, baseline, filepath.Dir(tcPath))
+	block := renderGeneratedBlock(rows, detect, baseline, relPath(filepath.Dir(tcPath), detectPath))
+
+	existing := ""
+	if data, readErr := os.ReadFile(tcPath); readErr == nil {
+		existing = string(data)
+	}
+	merged, err := mergeGeneratedBlock(existing, block)
+	if err != nil {
+		printBlocked([]string{fmt.Sprintf("%s: %v", filepath.ToSlash(tcPath), err)}, nil)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(tcPath), 0o755); err != nil {
+		printBlocked([]string{fmt.Sprintf("failed to create output directory: %v", err)}, nil)
+		return
+	}
+	if err := os.WriteFile(tcPath, []byte(merged), 0o644); err != nil {
+		printBlocked([]string{fmt.Sprintf("failed to write technology constraints: %v", err)}, nil)
+		return
+	}
+
+	unresolved := []string{}
+	for _, row := range rows {
+		if row.ApprovalStatus == "BLOCKED" && coreDecisions[row.Decision] {
+			unresolved = append(unresolved, row.Decision)
+		}
+	}
+
+	details := map[string]any{
+		"tc_file":                  filepath.ToSlash(tcPath),
+		"spec_tech_file":           filepath.ToSlash(detectPath),
+		"corporate_tech_file":      filepath.ToSlash(techFile),
+		"corporate_baseline_error": baselineError,
+		"constraints":              rows,
+		"unresolved_core":          unresolved,
+	}
+	if len(unresolved) > 0 {
+		printBlocked([]string{"core technology decisions unresolved after spec-tech-detect"}, details)
+		return
+	}
+
+	details["status"] = "PASS"
+	payload, _ := json.Marshal(details)
+	fmt.Println(string(payload))
+}
+
+func resolvePath(root, value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return ""
+	}
+	if !filepath.IsAbs(value) {
+		value = filepath.Join(root, filepath.FromSlash(value))
+	}
+	return filepath.Clean(value)
+}
+
+func relPath(base, target string) string {
+	rel, err := filepath.Rel(base, target)
+	if err != nil {
+		return filepath.ToSlash(target)
+	}
+	return filepath.ToSlash(rel)
+}
+
+func loadSpecTechDetect(path string) (*specTechDetect, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var detect specTechDetect
+	if err := json.Unmarshal(data, &detect); err != nil {
+		return nil, err
+	}
+	if detect.Detected == nil {
+		detect.Detected = map[string]techDecisionCandidate{}
+	}
+	return &detect, nil
+}
+
+func loadApprovedTechBaseline(path string) (*approvedTechBaseline, string) {
+	if strings.TrimSpace(path) == "" {
+		return nil, ""
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err.Error()
+	}
+	var baseline approvedTechBaseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, err.Error()
+	}
+	if strings.ToUpper(strings.TrimSpace(baseline.ApprovalStatus)) != "APPROVED" {
+		return nil, "baseline approval status is not APPROVED"
+	}
+	return &baseline, ""
+}
+
+func baselineValue(baseline *approvedTechBaseline, decision string) string {
+	if baseline == nil {
+		return ""
+	}
+	switch decision {
+	case "backend_runtime":
+		return baseline.Decisions.BackendRuntime
+	case "frontend_framework":
+		return baseline.Decisions.FrontendFramework
+	case "persistent_engine":
+		return baseline.Decisions.PersistentEngine
+	case "cache_engine":
+		if strings.TrimSpace(baseline.Decisions.RedisVersion) != "" {
+			return "redis"
+		}
+	case "migration_tool":
+		return baseline.Decisions.MigrationTool
+	}
+	return ""
+}
+
+// proposedAdapterID returns the adapter an infrastructure decision implies.
+// Runtime, UI framework and migration tooling do not map to adapters.
+func proposedAdapterID(decision, value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch decision {
+	case "persistent_engine":
+		return value
+	case "cache_engine":
+		if value == "memory" || value == "none" {
+			return ""
+		}
+		return value
+	}
+	return ""
+}
+
+func buildConstraintRows(detect *specTechDetect, baseline *approvedTechBaseline, tcDir string) []constraintRow {
+	rows := make([]constraintRow, 0, len(decisionOrder))
+	for i, decision := range decisionOrder {
+		row := constraintRow{TCID: fmt.Sprintf("TC-%03d", i+1), Decision: decision}
+		candidate, ok := detect.Detected[decision]
+		if !ok || strings.TrimSpace(candidate.Value) == "" {
+			row.Value = "<unresolved>"
+			row.Evidence = "-"
+			row.ApprovalStatus = "BLOCKED"
+			rows = append(rows, row)
+			continue
+		}
+		row.Value = strings.TrimSpace(candidate.Value)
+		row.Evidence = evidenceLink(detect.SpecDir, candidate, tcDir)
+		row.ApprovalStatus = "PROPOSED"
+		if approved := baselineValue(baseline, decision); approved != "" && strings.EqualFold(strings.TrimSpace(approved), row.Value) {
+			row.ApprovalStatus = "APPROVED"
+		}
+		row.AdapterID = proposedAdapterID(decision, row.Value)
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func evidenceLink(specDir string, candidate techDecisionCandidate, tcDir string) string {
+	file := filepath.FromSlash(strings.TrimSpace(candidate.File))
+	if file == "" {
+		return "-"
+	}
+	if !filepath.IsAbs(file) && strings.TrimSpace(specDir) != "" {
+		file = filepath.Join(specDir, file)
+	}
+	label := fmt.Sprintf("%s:%d", filepath.Base(file), candidate.Line)
+	if strings.TrimSpace(candidate.MatchedOn) == "corporate approved baseline" {
+		label = "corporate approved baseline"
+	}
+	return fmt.Sprintf("[%s](%s#L%d)", label, relPath(tcDir, file), candidate.Line)
+}
+
+func renderGeneratedBlock(rows []constraintRow, detect *specTechDetect, baseline *approvedTechBaseline, detectRel string) string {
+	lines := []string{
+		generatedBegin,
+		"## Detected Technology Constraints",
+		"",
+		"<!-- Regenerated by technology_constraints_materialize; edit outside this block. -->",
+		fmt.Sprintf("- Source: `%s`", detectRel),
+		fmt.Sprintf("- Detection timestamp (UTC): %s", valueOr(detect.TimestampUTC, "<unknown>")),
+	}
+	if baseline != nil {
+		lines = append(lines,
+			fmt.Sprintf("- Authoritative Source: %s", valueOr(baseline.AuthoritySource, "<unset>")),
+			fmt.Sprintf("- Approval Owner: %s", valueOr(baseline.ApprovalOwner, "<unset>")),
+		)
+	} else {
+		lines = append(lines, "- Authoritative Source: <no approved corporate baseline loaded>")
+	}
+	lines = append(lines,
+		"",
+		"| TC ID | Decision | Value | Evidence | Approval Status | Proposed Adapter |",
+		"|---|---|---|---|---|---|",
+	)
+	for _, row := range rows {
+		adapter := "-"
+		if row.AdapterID != "" {
+			adapter = "adapter_id: " + row.AdapterID
+		}
+		lines = append(lines, fmt.Sprintf("| %s | %s | %s | %s | %s | %s |", row.TCID, row.Decision, row.Value, row.Evidence, row.ApprovalStatus, adapter))
+	}
+	lines = append(lines, generatedEnd)
+	return strings.Join(lines, "\n")
+}
+
+// mergeGeneratedBlock replaces the generated block in place, or appends it when
+// the file has none, so manual content outside the markers is preserved. A
+// begin marker without an end marker is an error: appending would add another
+// block on every run.
+func mergeGeneratedBlock(existing, block string) (string, error) {
+	if strings.TrimSpace(existing) == "" {
+		return "# Technology Constraints\n\n" + block + "\n", nil
+	}
+	start := strings.Index(existing, generatedBegin)
+	if start >= 0 {
+		end := strings.Index(existing[start:], generatedEnd)
+		if end < 0 {
+			return "", fmt.Errorf("BEGIN GENERATED marker without a matching END GENERATED marker; restore the end marker or remove the partial block")
+		}
+		end += start + len(generatedEnd)
+		return existing[:start] + block + existing[end:], nil
+	}
+	return strings.TrimRight(existing, "\n") + "\n\n" + block + "\n", nil
+}
+
+func valueOr(value, fallback string) string {
+	if strings.TrimSpace(value) == "" {
+		return fallback
+	}
+	return strings.TrimSpace(value)
+}
+
+func printBlocked(issues []string, details map[string]any) {
+	payload := map[string]any{
+		"status": "BLOCKED",
+		"issues": issues,
+	}
+	for k, v := range details {
+		payload[k] = v
+	}
+	data, _ := json.Marshal(payload)
+	fmt.Println(string(data))
+}
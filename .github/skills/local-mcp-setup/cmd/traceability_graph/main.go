@@ -0,0 +1,404 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// traceIDPattern requires a digit in the suffix so template placeholders such
+// as REQ-xxx are not treated as IDs.
+var (
+	traceIDPattern  = regexp.MustCompile(`\b(REQ|PLAN|DIAG|TEST|DEF|TC)-[A-Za-z0-9]*[0-9][A-Za-z0-9]*\b`)
+	planFilePattern = regexp.MustCompile(`^(PLAN-[A-Za-z0-9]*[0-9][A-Za-z0-9]*)-`)
+)
+
+// typeRank orders ID types along the REQ -> PLAN -> DIAG -> TEST/DEF chain;
+// technology constraints sit beside requirements as inputs to plans.
+var typeRank = map[string]int{
+	"REQ":  0,
+	"TC":   0,
+	"PLAN": 1,
+	"DIAG": 2,
+	"TEST": 3,
+	"DEF":  3,
+}
+
+type traceNode struct {
+	ID         string   `json:"id"`
+	Type       string   `json:"type"`
+	DefinedIn  []string `json:"defined_in"`
+	References []string `json:"references"`
+}
+
+type traceEdge struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Source string `json:"source"`
+}
+
+type traceGaps struct {
+	RequirementsWithoutPlan []string `json:"requirements_without_plan"`
+	PlansWithoutTest        []string `json:"plans_without_test"`
+	DanglingReferences      []string `json:"dangling_references"`
+	OrphanIDs               []string `json:"orphan_ids"`
+}
+
+type traceGraph struct {
+	nodes map[string]*traceNode
+	edges map[string]traceEdge
+}
+
+func main() {
+	targetRoot := flag.String("target-root", "", "target repository root (defaults to cwd)")
+	outJSON := flag.String("out-json", "docs/tooling/traceability-graph.json", "graph JSON output path")
+	outMermaid := flag.String("out-mermaid", "docs/tooling/traceability-graph.mmd", "graph Mermaid output path")
+	flag.Parse()
+
+	root := strings.TrimSpace(*targetRoot)
+	if root == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			printBlocked([]string{"unable to determine working directory"}, nil)
+			return
+		}
+		root = cwd
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		printBlocked([]string{"invalid target root"}, nil)
+		return
+	}
+
+	sources := collectSources(absRoot)
+	graph := &traceGraph{nodes: map[string]*traceNode{}, edges: map[string]traceEdge{}}
+	for _, rel := range sources {
+		data, readErr := os.ReadFile(filepath.Join(absRoot, filepath.FromSlash(rel)))
+		if readErr != nil {
+			continue
+		}
+		graph.scan(rel, string(data))
+	}
+	nodes, edges := graph.sorted()
+	gaps := graph.gaps()
+
+	jsonPath := resolvePath(absRoot, *outJSON)
+	mermaidPath := resolvePath(absRoot, *outMermaid)
+	document := map[string]any{
+		"sources": sources,
+		"nodes":   nodes,
+		"edges":   edges,
+		"gaps":    gaps,
+	}
+	if err := writeFile(jsonPath, mustJSON(document)); err != nil {
+		printBlocked([]string{fmt.Sprintf("failed to write graph JSON: %v", err)}, nil)
+		return
+	}
+	if err := writeFile(mermaidPath, renderMermaid(nodes, edges)); err != nil {
+		printBlocked([]string{fmt.Sprintf("failed to write Mermaid graph: %v", err)}, nil)
+		return
+	}
+
+	details := map[string]any{
+		"graph_json":    filepath.ToSlash(jsonPath),
+		"graph_mermaid": filepath.ToSlash(mermaidPath),
+		"node_count":    len(nodes),
+		"edge_count":    len(edges),
+		"sources":       len(sources),
+		"gaps":          gaps,
+	}
+	if len(gaps.RequirementsWithoutPlan)+len(gaps.PlansWithoutTest)+len(gaps.DanglingReferences)+len(gaps.OrphanIDs) > 0 {
+		printBlocked([]string{"traceability graph has gaps"}, details)
+		return
+	}
+
+	details["status"] = "PASS"
+	payload, _ := json.Marshal(details)
+	fmt.Println(string(payload))
+}
+
+// collectSources lists markdown under docs/ and each repos/*/docs/, skipping
+// generated tooling output.
+func collectSources(root string) []string {
+	dirs := []string{filepath.Join(root, "docs")}
+	if entries, err := os.ReadDir(filepath.Join(root, "repos")); err == nil {
+		for _, entry := range entries {
+			if entry.IsDir() {
+				dirs = append(dirs, filepath.Join(root, "repos", entry.Name(), "docs"))
+			}
+		}
+	}
+	sources := []string{}
+	for _, dir := range dirs {
+		_ = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if d.IsDir() {
+				if d.Name() == "tooling" {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !strings.HasSuffix(strings.ToLower(d.Name()), ".md") {
+				return nil
+			}
+			if rel, relErr := filepath.Rel(root, path); relErr == nil {
+				sources = append(sources, filepath.ToSlash(rel))
+			}
+			return nil
+		})
+	}
+	sort.Strings(sources)
+	return sources
+}
+
+func (g *traceGraph) node(id string) *traceNode {
+	n, ok := g.nodes[id]
+	if !ok {
+		n = &traceNode{ID: id, Type: strings.SplitN(id, "-", 2)[0], DefinedIn: []string{}, References: []string{}}
+		g.nodes[id] = n
+	}
+	return n
+}
+
+func (g *traceGraph) define(id, location string) {
+	n := g.node(id)
+	n.DefinedIn = appendUnique(n.DefinedIn, location)
+}
+
+func (g *traceGraph) reference(id, location string) {
+	n := g.node(id)
+	n.References = appendUnique(n.References, location)
+}
+
+func (g *traceGraph) link(from, to, location string) {
+	if from == to {
+		return
+	}
+	key := from + "->" + to
+	if _, ok := g.edges[key]; !ok {
+		g.edges[key] = traceEdge{From: from, To: to, Source: location}
+	}
+}
+
+// scan records definitions and edges from one document. IDs are defined by
+// ID inventory entries, headings, first table cells and PLAN file names.
+// Edges come from `A -> B` chains and from table rows that mention IDs of
+// successive chain types.
+func (g *traceGraph) scan(rel, content string) {
+	if match := planFilePattern.FindStringSubmatch(filepath.Base(rel)); match != nil {
+		g.define(match[1], rel)
+	}
+	isPlanIndex := strings.HasSuffix(rel, "plans/index.md")
+	section := ""
+	for i, raw := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		line := strings.TrimSpace(raw)
+		location := fmt.Sprintf("%s:%d", rel, i+1)
+		if strings.HasPrefix(line, "#") {
+			section = strings.ToLower(strings.TrimSpace(strings.TrimLeft(line, "#")))
+		}
+		ids := traceIDPattern.FindAllString(line, -1)
+		if len(ids) == 0 {
+			continue
+		}
+		for _, id := range ids {
+			g.reference(id, location)
+		}
+
+		switch {
+		case strings.HasPrefix(line, "#"):
+			for _, id := range ids {
+				g.define(id, location)
+			}
+		case section == "id inventory" && strings.HasPrefix(line, "-"):
+			for _, id := range ids {
+				g.define(id, location)
+			}
+		case strings.HasPrefix(line, "|"):
+			cells := strings.Split(strings.Trim(line, "|"), "|")
+			if !isPlanIndex {
+				for _, id := range traceIDPattern.FindAllString(cells[0], -1) {
+					g.define(id, location)
+				}
+			}
+			g.linkByRank(ids, location)
+		}
+
+		if strings.Contains(line, "->") {
+			segments := strings.Split(line, "->")
+			for s := 0; s+1 < len(segments); s++ {
+				for _, from := range traceIDPattern.FindAllString(segments[s], -1) {
+					for _, to := range traceIDPattern.FindAllString(segments[s+1], -1) {
+						g.link(from, to, location)
+					}
+				}
+			}
+		}
+	}
+}
+
+// linkByRank connects the IDs of each rank present in a row to the IDs of
+// the next rank present, e.g. REQ-001 | PLAN-001 | TEST-003.
+func (g *traceGraph) linkByRank(ids []string, location string) {
+	byRank := map[int][]string{}
+	for _, id := range ids {
+		rank := typeRank[strings.SplitN(id, "-", 2)[0]]
+		byRank[rank] = appendUnique(byRank[rank], id)
+	}
+	ranks := make([]int, 0, len(byRank))
+	for rank := range byRank {
+		ranks = append(ranks, rank)
+	}
+	sort.Ints(ranks)
+	for r := 0; r+1 < len(ranks); r++ {
+		for _, from := range byRank[ranks[r]] {
+			for _, to := range byRank[ranks[r+1]] {
+				g.link(from, to, location)
+			}
+		}
+	}
+}
+
+func (g *traceGraph) sorted() ([]traceNode, []traceEdge) {
+	nodes := make([]traceNode, 0, len(g.nodes))
+	for _, n := range g.nodes {
+		nodes = append(nodes, *n)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	edges := make([]traceEdge, 0, len(g.edges))
+	for _, e := range g.edges {
+		edges = append(edges, e)
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
+	return nodes, edges
+}
+
+// gaps reports the coverage holes. Technology constraints apply to the whole
+// project, so an unlinked TC row is not an orphan.
+func (g *traceGraph) gaps() traceGaps {
+	out := map[string][]string{}
+	for _, e := range g.edges {
+		out[e.From] = append(out[e.From], e.To)
+	}
+	linked := map[string]bool{}
+	for _, e := range g.edges {
+		linked[e.From] = true
+		linked[e.To] = true
+	}
+
+	gaps := traceGaps{RequirementsWithoutPlan: []string{}, PlansWithoutTest: []string{}, DanglingReferences: []string{}, OrphanIDs: []string{}}
+	for id, n := range g.nodes {
+		if len(n.DefinedIn) == 0 {
+			gaps.DanglingReferences = append(gaps.DanglingReferences, id)
+		} else if !linked[id] && n.Type != "TC" {
+			gaps.OrphanIDs = append(gaps.OrphanIDs, id)
+		}
+		switch n.Type {
+		case "REQ":
+			if !reaches(id, out, "PLAN") {
+				gaps.RequirementsWithoutPlan = append(gaps.RequirementsWithoutPlan, id)
+			}
+		case "PLAN":
+			if !reaches(id, out, "TEST") {
+				gaps.PlansWithoutTest = append(gaps.PlansWithoutTest, id)
+			}
+		}
+	}
+	sort.Strings(gaps.RequirementsWithoutPlan)
+	sort.Strings(gaps.PlansWithoutTest)
+	sort.Strings(gaps.DanglingReferences)
+	sort.Strings(gaps.OrphanIDs)
+	return gaps
+}
+
+// reaches reports whether any node of the wanted type is reachable from id.
+func reaches(id string, out map[string][]string, wanted string) bool {
+	seen := map[string]bool{id: true}
+	queue := []string{id}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range out[current] {
+			if seen[next] {
+				continue
+			}
+			if strings.HasPrefix(next, wanted+"-") {
+				return true
+			}
+			seen[next] = true
+			queue = append(queue, next)
+		}
+	}
+	return false
+}
+
+func renderMermaid(nodes []traceNode, edges []traceEdge) string {
+	lines := []string{"graph LR"}
+	for _, n := range nodes {
+		shape := fmt.Sprintf("%s[\"%s\"]", mermaidID(n.ID), n.ID)
+		if len(n.DefinedIn) == 0 {
+			shape = fmt.Sprintf("%s{{\"%s (undefined)\"}}", mermaidID(n.ID), n.ID)
+		}
+		lines = append(lines, "  "+shape)
+	}
+	for _, e := range edges {
+		lines = append(lines, fmt.Sprintf("  %s --> %s", mermaidID(e.From), mermaidID(e.To)))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func mermaidID(id string) string {
+	return strings.ReplaceAll(id, "-", "_")
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+func resolvePath(root, value string) string {
+	value = strings.TrimSpace(value)
+	if !filepath.IsAbs(value) {
+		value = filepath.Join(root, filepath.FromSlash(value))
+	}
+	return filepath.Clean(value)
+}
+
+func mustJSON(value any) string {
+	data, _ := json.MarshalIndent(value, "", "  ")
+	return string(data) + "\n"
+}
+
+func writeFile(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(content), 0o644)
+}
+
+func printBlocked(issues []string, details map[string]any) {
+	payload := map[string]any{
+		"status": "BLOCKED",
+		"issues": issues,
+	}
+	for k, v := range details {
+		payload[k] = v
+	}
+	data, _ := json.Marshal(payload)
+	fmt.Println(string(data))
+}
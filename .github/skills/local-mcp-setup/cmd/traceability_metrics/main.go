@@ -0,0 +1,312 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	traceIDPattern = regexp.MustCompile(`\b(REQ|PLAN|DIAG|TEST|DEF|TC)-[A-Za-z0-9]*[0-9][A-Za-z0-9]*\b`)
+	defIDPattern   = regexp.MustCompile(`\bDEF-[A-Za-z0-9]*[0-9][A-Za-z0-9]*\b`)
+	closedPattern  = regexp.MustCompile(`(?i)\b(closed|resolved|fixed|verified|done)\b`)
+)
+
+type ratio struct {
+	Covered int     `json:"covered"`
+	Total   int     `json:"total"`
+	Percent float64 `json:"percent"`
+}
+
+type coverage struct {
+	ReqToPlan  ratio    `json:"req_to_plan"`
+	PlanToTest ratio    `json:"plan_to_test"`
+	DefClosure ratio    `json:"def_closure"`
+	Uncovered  []string `json:"uncovered,omitempty"`
+}
+
+type metricsSnapshot struct {
+	TimestampUTC string              `json:"timestamp_utc"`
+	Totals       coverage            `json:"totals"`
+	Scopes       map[string]coverage `json:"scopes"`
+}
+
+type metricsReport struct {
+	metricsSnapshot
+	Previous *metricsSnapshot   `json:"previous,omitempty"`
+	Trend    map[string]float64 `json:"trend,omitempty"`
+}
+
+// packData is what one traceability pack contributes: its ID inventory,
+// mapping edges and the DEF IDs recorded as closed.
+type packData struct {
+	inventory map[string]bool
+	edges     map[string][]string
+	closed    map[string]bool
+}
+
+func main() {
+	targetRoot := flag.String("target-root", "", "target repository root (defaults to cwd)")
+	outPath := flag.String("out", "docs/tooling/traceability-metrics.json", "metrics output path; its previous content is the trend baseline")
+	flag.Parse()
+
+	root := strings.TrimSpace(*targetRoot)
+	if root == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			printBlocked([]string{"unable to determine working directory"}, nil)
+			return
+		}
+		root = cwd
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		printBlocked([]string{"invalid target root"}, nil)
+		return
+	}
+
+	scopes := map[string]string{"system": absRoot}
+	if entries, readErr := os.ReadDir(filepath.Join(absRoot, "repos")); readErr == nil {
+		for _, entry := range entries {
+			if entry.IsDir() {
+				scopes["repos/"+entry.Name()] = filepath.Join(absRoot, "repos", entry.Name())
+			}
+		}
+	}
+
+	packs := map[string]packData{}
+	missing := []string{}
+	for scope, dir := range scopes {
+		packPath := filepath.Join(dir, "docs", "handoffs", "traceability-pack.md")
+		data, readErr := os.ReadFile(packPath)
+		if readErr != nil {
+			rel, _ := filepath.Rel(absRoot, packPath)
+			missing = append(missing, filepath.ToSlash(rel))
+			continue
+		}
+		pack := parsePack(string(data))
+		markChangelogFixed(filepath.Join(dir, "CHANGELOG.md"), pack.closed)
+		packs[scope] = pack
+	}
+	sort.Strings(missing)
+	if len(packs) == 0 {
+		printBlocked([]string{"no traceability packs found"}, map[string]any{"missing_packs": missing})
+		return
+	}
+
+	combined := packData{inventory: map[string]bool{}, edges: map[string][]string{}, closed: map[string]bool{}}
+	snapshot := metricsSnapshot{TimestampUTC: time.Now().UTC().Format(time.RFC3339), Scopes: map[string]coverage{}}
+	for scope, pack := range packs {
+		snapshot.Scopes[scope] = computeCoverage(pack)
+		for id := range pack.inventory {
+			combined.inventory[id] = true
+		}
+		for from, targets := range pack.edges {
+			combined.edges[from] = append(combined.edges[from], targets...)
+		}
+		for id := range pack.closed {
+			combined.closed[id] = true
+		}
+	}
+	snapshot.Totals = computeCoverage(combined)
+
+	metricsPath := strings.TrimSpace(*outPath)
+	if !filepath.IsAbs(metricsPath) {
+		metricsPath = filepath.Join(absRoot, filepath.FromSlash(metricsPath))
+	}
+	report := metricsReport{metricsSnapshot: snapshot}
+	if previous := loadPrevious(metricsPath); previous != nil {
+		report.Previous = previous
+		report.Trend = map[string]float64{
+			"req_to_plan":  round(snapshot.Totals.ReqToPlan.Percent - previous.Totals.ReqToPlan.Percent),
+			"plan_to_test": round(snapshot.Totals.PlanToTest.Percent - previous.Totals.PlanToTest.Percent),
+			"def_closure":  round(snapshot.Totals.DefClosure.Percent - previous.Totals.DefClosure.Percent),
+		}
+	}
+
+	data, _ := json.MarshalIndent(report, "", "  ")
+	if err := os.MkdirAll(filepath.Dir(metricsPath), 0o755); err != nil {
+		printBlocked([]string{fmt.Sprintf("failed to create output directory: %v", err)}, nil)
+		return
+	}
+	if err := os.WriteFile(metricsPath, append(data, '\n'), 0o644); err != nil {
+		printBlocked([]string{fmt.Sprintf("failed to write metrics: %v", err)}, nil)
+		return
+	}
+
+	payload, _ := json.Marshal(map[string]any{
+		"status":        "PASS",
+		"metrics_file":  filepath.ToSlash(metricsPath),
+		"totals":        snapshot.Totals,
+		"trend":         report.Trend,
+		"missing_packs": missing,
+	})
+	fmt.Println(string(payload))
+}
+
+// parsePack reads the `## ID Inventory` bullets and `## Mapping` chains of a
+// traceability pack. A DEF is closed when its inventory or mapping line
+// carries a closed/resolved/fixed marker.
+func parsePack(content string) packData {
+	pack := packData{inventory: map[string]bool{}, edges: map[string][]string{}, closed: map[string]bool{}}
+	section := ""
+	for _, raw := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		line := strings.TrimSpace(raw)
+		if strings.HasPrefix(line, "## ") {
+			section = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(line, "## ")))
+			continue
+		}
+		switch section {
+		case "id inventory":
+			if !strings.HasPrefix(line, "-") {
+				continue
+			}
+			for _, id := range traceIDPattern.FindAllString(line, -1) {
+				pack.inventory[id] = true
+			}
+		case "mapping":
+			segments := strings.Split(line, "->")
+			for s := 0; s+1 < len(segments); s++ {
+				for _, from := range traceIDPattern.FindAllString(segments[s], -1) {
+					pack.edges[from] = append(pack.edges[from], traceIDPattern.FindAllString(segments[s+1], -1)...)
+				}
+			}
+		default:
+			continue
+		}
+		if closedPattern.MatchString(line) {
+			for _, id := range defIDPattern.FindAllString(line, -1) {
+				pack.closed[id] = true
+			}
+		}
+	}
+	return pack
+}
+
+// markChangelogFixed treats DEF IDs listed under a `### Fixed` heading of the
+// scope's CHANGELOG.md as closed.
+func markChangelogFixed(path string, closed map[string]bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	inFixed := false
+	for _, raw := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		line := strings.TrimSpace(raw)
+		if strings.HasPrefix(line, "#") {
+			inFixed = strings.EqualFold(strings.TrimSpace(strings.TrimLeft(line, "#")), "fixed")
+			continue
+		}
+		if inFixed {
+			for _, id := range defIDPattern.FindAllString(line, -1) {
+				closed[id] = true
+			}
+		}
+	}
+}
+
+func computeCoverage(pack packData) coverage {
+	result := coverage{}
+	uncovered := []string{}
+	for _, id := range sortedIDs(pack.inventory) {
+		switch {
+		case strings.HasPrefix(id, "REQ-"):
+			result.ReqToPlan.Total++
+			if reaches(id, pack.edges, "PLAN") {
+				result.ReqToPlan.Covered++
+			} else {
+				uncovered = append(uncovered, id)
+			}
+		case strings.HasPrefix(id, "PLAN-"):
+			result.PlanToTest.Total++
+			if reaches(id, pack.edges, "TEST") {
+				result.PlanToTest.Covered++
+			} else {
+				uncovered = append(uncovered, id)
+			}
+		case strings.HasPrefix(id, "DEF-"):
+			result.DefClosure.Total++
+			if pack.closed[id] {
+				result.DefClosure.Covered++
+			}
+		}
+	}
+	result.ReqToPlan.Percent = percent(result.ReqToPlan)
+	result.PlanToTest.Percent = percent(result.PlanToTest)
+	result.DefClosure.Percent = percent(result.DefClosure)
+	result.Uncovered = uncovered
+	return result
+}
+
+// reaches reports whether any ID of the wanted type is reachable from id.
+func reaches(id string, edges map[string][]string, wanted string) bool {
+	seen := map[string]bool{id: true}
+	queue := []string{id}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range edges[current] {
+			if seen[next] {
+				continue
+			}
+			if strings.HasPrefix(next, wanted+"-") {
+				return true
+			}
+			seen[next] = true
+			queue = append(queue, next)
+		}
+	}
+	return false
+}
+
+// percent reports 100 for an empty denominator: nothing to cover is complete.
+func percent(r ratio) float64 {
+	if r.Total == 0 {
+		return 100
+	}
+	return round(float64(r.Covered) * 100 / float64(r.Total))
+}
+
+func round(value float64) float64 {
+	return math.Round(value*10) / 10
+}
+
+func sortedIDs(values map[string]bool) []string {
+	out := make([]string, 0, len(values))
+	for value := range values {
+		out = append(out, value)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func loadPrevious(path string) *metricsSnapshot {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var previous metricsReport
+	if err := json.Unmarshal(data, &previous); err != nil || previous.TimestampUTC == "" {
+		return nil
+	}
+	return &previous.metricsSnapshot
+}
+
+func printBlocked(issues []string, details map[string]any) {
+	payload := map[string]any{
+		"status": "BLOCKED",
+		"issues": issues,
+	}
+	for k, v := range details {
+		payload[k] = v
+	}
+	data, _ := json.Marshal(payload)
+	fmt.Println(string(data))
+}
@@ -16,6 +16,7 @@ type result struct {
 	Created      bool     `json:"created"`
 	CreatedDirs  []string `json:"created_dirs,omitempty"`
 	CreatedFiles []string `json:"created_files,omitempty"`
+	FollowUp     []string `json:"follow_up,omitempty"`
 	Issues       []string `json:"issues,omitempty"`
 }
 
@@ -107,10 +108,13 @@ func main() {
 
 	writeFile(filepath.Join(targetAbs, "README.md"), "# openapi-contracts\n\n## Purpose\n- Canonical interface contract repository.\n\n## Scope\n- In scope: API contract definitions and compatibility governance.\n- Out of scope: service runtime implementation.\n\n## Traceability\n- REQ IDs:\n- PLAN IDs:\n- DIAG IDs:\n")
 	writeFile(filepath.Join(targetAbs, "CHANGELOG.md"), "# Changelog\n\n## [Unreleased]\n")
-	writeFile(filepath.Join(targetAbs, "openapi", "openapi.yaml"), "openapi: 3.0.3\ninfo:\n  title: Contract Placeholder\n  version: 0.1.0\npaths: {}\n")
+	writeFile(filepath.Join(targetAbs, "openapi", "openapi.yaml"), "openapi: 3.1.0\ninfo:\n  title: Contract Placeholder\n  version: 0.1.0\npaths: {}\n")
 	writeFile(filepath.Join(targetAbs, "docs", "current-state", "implementation-summary.md"), "# Implementation Summary\n\n## Plan intent\n-\n\n## Key implementation details\n-\n")
 	writeFile(filepath.Join(targetAbs, "docs", "handoffs", "traceability-pack.md"), "# Handoff Traceability Pack - OpenAPI Repo\n\n## ID Inventory\n- REQ:\n- PLAN:\n- DIAG:\n- TEST:\n- DEF:\n- TC:\n")
 
+	// The placeholder contract fails openapi_lint (placeholder title, empty
+	// paths) by design: the lint stays BLOCKED until a real contract exists.
+	res.FollowUp = append(res.FollowUp, fmt.Sprintf("%s/openapi/openapi.yaml: replace the placeholder info.title and define paths; openapi_lint reports placeholder-title and empty-paths until then", filepath.ToSlash(targetRel)))
 	res.Created = true
 	emit(res)
 }
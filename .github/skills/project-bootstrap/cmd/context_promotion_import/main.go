@@ -0,0 +1,536 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+type importReport struct {
+	Status         string            `json:"status"`
+	TargetRoot     string            `json:"target_root"`
+	ProjectSlug    string            `json:"project_slug"`
+	PromotionIndex string            `json:"promotion_index"`
+	PromotionScan  string            `json:"promotion_scan,omitempty"`
+	TechChoices    map[string]string `json:"tech_choices"`
+	Candidates     []candidate       `json:"candidates"`
+	Imported       []importedFile    `json:"imported"`
+	Missing        []string          `json:"missing,omitempty"`
+	ImportRoot     string            `json:"import_root"`
+	Issues         []string          `json:"issues"`
+	TimestampUTC   string            `json:"timestamp_utc"`
+}
+
+// candidate is a promoted project whose tech choices overlap this project's.
+type candidate struct {
+	Slug     string   `json:"slug"`
+	Version  string   `json:"version"`
+	Score    int      `json:"score"`
+	Matched  []string `json:"matched"`
+	Selected bool     `json:"selected"`
+}
+
+type importedFile struct {
+	Slug        string `json:"slug"`
+	Kind        string `json:"kind"`
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	SHA256      string `json:"sha256"`
+}
+
+// promotionIndexEntry mirrors the catalog index written by
+// context_promotion_publish.
+type promotionIndexEntry struct {
+	Slug         string              `json:"slug"`
+	TimestampUTC string              `json:"timestamp_utc"`
+	Version      string              `json:"version"`
+	SourceCommit string              `json:"source_commit"`
+	Files        map[string][]string `json:"files"`
+	TechChoices  map[string]string   `json:"tech_choices"`
+	// text holds the promoted documents of a scanned (not indexed) project;
+	// tech choices are matched against it when TechChoices is unknown.
+	text string
+}
+
+const (
+	catalogIndexPath          = "docs/artifacts/promotions/index.json"
+	architecturePromotionsDir = "docs/source/02-architecture/promotions"
+)
+
+var adrFile = regexp.MustCompile(`(?i)^ADR-[^/]*\.md$`)
+
+// importKinds maps each importable kind to the promoted files it covers.
+var importKinds = map[string]func(name string) bool{
+	"adr":               func(name string) bool { return adrFile.MatchString(name) },
+	"data-architecture": func(name string) bool { return name == "data-architecture-decision.md" },
+	"compose-mode":      func(name string) bool { return name == "compose-mode-decision.md" },
+}
+
+func main() {
+	targetRoot := flag.String("target-root", "", "target repository root (defaults to cwd)")
+	archRepoRoot := flag.String("architecture-repo-root", "", "architecture standards repo root (defaults to ARCHITECTURE_REPO_ROOT)")
+	catalogRepoRoot := flag.String("catalog-repo-root", "", "library catalog repo root holding the promotion index (defaults to CATALOG_REPO_ROOT)")
+	projectSlug := flag.String("project-slug", "", "this project's slug, excluded from candidates (defaults to the target directory name)")
+	specTechFile := flag.String("spec-tech-file", "docs/tooling/spec-tech-detect.json", "spec-tech-detect output path")
+	minMatch := flag.Int("min-match", 2, "minimum number of matching tech choices for a promoted project to qualify")
+	limit := flag.Int("limit", 3, "maximum number of projects to import from, best match first (0 for no limit)")
+	selectSlugs := flag.String("select", "", "comma-separated slugs to import from instead of the best matches")
+	kinds := flag.String("kinds", "adr,data-architecture,compose-mode", "comma-separated kinds to import: adr, data-architecture, compose-mode")
+	importDir := flag.String("import-dir", "docs/source/imported", "directory (relative to target root) receiving the read-only references")
+	flag.Parse()
+
+	root := strings.TrimSpace(*targetRoot)
+	if root == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			printBlocked("", []string{"unable to determine working directory"})
+			return
+		}
+		root = cwd
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		printBlocked("", []string{"invalid target root"})
+		return
+	}
+
+	slug := strings.TrimSpace(*projectSlug)
+	if slug == "" {
+		slug = normalizeSlug(filepath.Base(absRoot))
+	}
+
+	wanted := map[string]bool{}
+	for _, kind := range strings.Split(*kinds, ",") {
+		kind = strings.TrimSpace(kind)
+		if kind == "" {
+			continue
+		}
+		if importKinds[kind] == nil {
+			printBlocked(absRoot, []string{fmt.Sprintf("unknown kind %q (use adr, data-architecture, compose-mode)", kind)})
+			return
+		}
+		wanted[kind] = true
+	}
+	importRel := strings.Trim(filepath.ToSlash(strings.TrimSpace(*importDir)), "/")
+	if importRel == "" || strings.Contains(importRel, "..") || filepath.IsAbs(*importDir) {
+		printBlocked(absRoot, []string{fmt.Sprintf("invalid --import-dir %q", *importDir)})
+		return
+	}
+
+	archRoot := resolveArgOrEnv(strings.TrimSpace(*archRepoRoot), "ARCHITECTURE_REPO_ROOT")
+	catalogRoot := resolveArgOrEnv(strings.TrimSpace(*catalogRepoRoot), "CATALOG_REPO_ROOT")
+	if catalogRoot == "" && archRoot == "" {
+		printBlocked(absRoot, []string{"no upstream repo configured; set CATALOG_REPO_ROOT (promotion index) or ARCHITECTURE_REPO_ROOT (promotion snapshots)"})
+		return
+	}
+	if catalogRoot != "" {
+		catalogRoot, _ = filepath.Abs(catalogRoot)
+	}
+	if archRoot != "" {
+		archRoot, _ = filepath.Abs(archRoot)
+	}
+
+	techChoices, err := loadTechChoices(resolvePath(absRoot, *specTechFile))
+	if err != nil {
+		printBlocked(absRoot, []string{fmt.Sprintf("failed to read spec-tech-detect output: %v", err)})
+		return
+	}
+	if len(techChoices) == 0 {
+		printBlocked(absRoot, []string{"spec-tech-detect output has no detected tech choices; run spec_tech_detect first"})
+		return
+	}
+
+	// Without a catalog index the architecture repo's promotion snapshots
+	// are scanned instead; tech choices are then matched against their text.
+	indexPath := ""
+	if catalogRoot != "" && isFile(filepath.Join(catalogRoot, filepath.FromSlash(catalogIndexPath))) {
+		indexPath = filepath.Join(catalogRoot, filepath.FromSlash(catalogIndexPath))
+	}
+	scanPath := ""
+	var entries []promotionIndexEntry
+	if indexPath != "" {
+		entries, err = loadPromotionIndex(indexPath)
+		if err != nil {
+			printBlocked(absRoot, []string{fmt.Sprintf("failed to read promotion index %s: %v", filepath.ToSlash(indexPath), err)})
+			return
+		}
+	} else {
+		if archRoot == "" {
+			printBlocked(absRoot, []string{fmt.Sprintf("promotion index %s not found and no architecture repo to scan; set ARCHITECTURE_REPO_ROOT or --architecture-repo-root", filepath.ToSlash(filepath.Join(catalogRoot, filepath.FromSlash(catalogIndexPath))))})
+			return
+		}
+		scanPath = filepath.Join(archRoot, filepath.FromSlash(architecturePromotionsDir))
+		entries, err = scanPromotions(archRoot)
+		if err != nil {
+			printBlocked(absRoot, []string{fmt.Sprintf("failed to scan promotions in %s: %v", filepath.ToSlash(scanPath), err)})
+			return
+		}
+	}
+
+	report := importReport{
+		Status:         "PASS",
+		TargetRoot:     filepath.ToSlash(absRoot),
+		ProjectSlug:    slug,
+		PromotionIndex: filepath.ToSlash(indexPath),
+		PromotionScan:  filepath.ToSlash(scanPath),
+		TechChoices:    techChoices,
+		Candidates:     []candidate{},
+		Imported:       []importedFile{},
+		ImportRoot:     importRel,
+		Issues:         []string{},
+		TimestampUTC:   time.Now().UTC().Format(time.RFC3339),
+	}
+
+	bySlug := map[string]promotionIndexEntry{}
+	for _, entry := range entries {
+		if entry.Slug == "" || entry.Slug == slug {
+			continue
+		}
+		bySlug[entry.Slug] = entry
+		matched := matchTech(techChoices, entry.TechChoices)
+		if entry.TechChoices == nil && entry.text != "" {
+			matched = mentionedTech(techChoices, entry.text)
+		}
+		if len(matched) == 0 {
+			continue
+		}
+		report.Candidates = append(report.Candidates, candidate{Slug: entry.Slug, Version: entry.Version, Score: len(matched), Matched: matched})
+	}
+	sort.Slice(report.Candidates, func(i, j int) bool {
+		if report.Candidates[i].Score != report.Candidates[j].Score {
+			return report.Candidates[i].Score > report.Candidates[j].Score
+		}
+		return report.Candidates[i].Slug < report.Candidates[j].Slug
+	})
+
+	selected := []string{}
+	if strings.TrimSpace(*selectSlugs) != "" {
+		for _, name := range strings.Split(*selectSlugs, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			if _, ok := bySlug[name]; !ok {
+				report.Issues = append(report.Issues, fmt.Sprintf("selected project %s is not in the promotion index", name))
+				continue
+			}
+			selected = append(selected, name)
+		}
+	} else {
+		for _, c := range report.Candidates {
+			if c.Score < *minMatch || (*limit > 0 && len(selected) >= *limit) {
+				continue
+			}
+			selected = append(selected, c.Slug)
+		}
+	}
+	isSelected := map[string]bool{}
+	for _, name := range selected {
+		isSelected[name] = true
+	}
+	for i := range report.Candidates {
+		report.Candidates[i].Selected = isSelected[report.Candidates[i].Slug]
+	}
+
+	upstreams := []string{}
+	for _, repo := range []string{archRoot, catalogRoot} {
+		if repo != "" {
+			upstreams = append(upstreams, repo)
+		}
+	}
+	timestamp := report.TimestampUTC
+	for _, name := range selected {
+		entry := bySlug[name]
+		for _, path := range promotedPaths(entry) {
+			kind := kindOf(filepath.Base(path))
+			if kind == "" || !wanted[kind] {
+				continue
+			}
+			repo, src := locate(upstreams, path)
+			if src == "" {
+				report.Missing = append(report.Missing, name+": "+path)
+				continue
+			}
+			destRel := importRel + "/" + name + "/" + domainRelative(path, entry.Version)
+			provenance := fmt.Sprintf("read-only reference imported by context_promotion_import from %s:%s (project %s, version %s, source commit %s, imported %s); do not edit, update the upstream promotion and re-import", filepath.ToSlash(repo), path, name, valueOr(entry.Version, "unknown"), valueOr(entry.SourceCommit, "unknown"), timestamp)
+			sum, writeErr := importFile(src, filepath.Join(absRoot, filepath.FromSlash(destRel)), provenance)
+			if writeErr != nil {
+				report.Issues = append(report.Issues, fmt.Sprintf("failed to import %s: %v", path, writeErr))
+				continue
+			}
+			report.Imported = append(report.Imported, importedFile{Slug: name, Kind: kind, Source: filepath.ToSlash(repo) + ":" + path, Destination: destRel, SHA256: sum})
+		}
+	}
+
+	if len(report.Issues) > 0 {
+		report.Status = "BLOCKED"
+	}
+
+	reportPath := filepath.Join(absRoot, "docs", "tooling", "context-promotion-import.json")
+	if err := os.MkdirAll(filepath.Dir(reportPath), 0o755); err == nil {
+		if data, err := json.MarshalIndent(report, "", "  "); err == nil {
+			_ = os.WriteFile(reportPath, data, 0o644)
+		}
+	}
+	printReport(report)
+}
+
+// loadTechChoices returns decision -> value from spec-tech-detect.json.
+func loadTechChoices(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var detect struct {
+		Detected map[string]struct {
+			Value string `json:"value"`
+		} `json:"detected"`
+	}
+	if err := json.Unmarshal(data, &detect); err != nil {
+		return nil, err
+	}
+	out := map[string]string{}
+	for decision, found := range detect.Detected {
+		if value := strings.TrimSpace(found.Value); value != "" {
+			out[decision] = value
+		}
+	}
+	return out, nil
+}
+
+func loadPromotionIndex(path string) ([]promotionIndexEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var index struct {
+		Promotions []promotionIndexEntry `json:"promotions"`
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, err
+	}
+	return index.Promotions, nil
+}
+
+// matchTech lists the decisions both projects made with the same value.
+func matchTech(ours, theirs map[string]string) []string {
+	matched := []string{}
+	for decision, value := range ours {
+		if other, ok := theirs[decision]; ok && strings.EqualFold(strings.TrimSpace(other), value) {
+			matched = append(matched, decision)
+		}
+	}
+	sort.Strings(matched)
+	return matched
+}
+
+func promotedPaths(entry promotionIndexEntry) []string {
+	paths := []string{}
+	for _, files := range entry.Files {
+		paths = append(paths, files...)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// scanPromotions builds index entries from <arch>/<promotions>/*/latest: each
+// slug's latest snapshot, with its promoted documents kept for tech matching.
+func scanPromotions(archRoot string) ([]promotionIndexEntry, error) {
+	base := filepath.Join(archRoot, filepath.FromSlash(architecturePromotionsDir))
+	pointers, err := filepath.Glob(filepath.Join(base, "*", "latest"))
+	if err != nil {
+		return nil, err
+	}
+	entries := []promotionIndexEntry{}
+	for _, pointer := range pointers {
+		data, readErr := os.ReadFile(pointer)
+		if readErr != nil {
+			return nil, readErr
+		}
+		version := strings.TrimSpace(string(data))
+		snapshot := filepath.Join(filepath.Dir(pointer), version)
+		if version == "" || strings.ContainsAny(version, "/\\") || strings.HasPrefix(version, ".") {
+			continue
+		}
+		entry := promotionIndexEntry{Slug: filepath.Base(filepath.Dir(pointer)), Version: version, Files: map[string][]string{}}
+		text := strings.Builder{}
+		_ = filepath.WalkDir(snapshot, func(path string, d os.DirEntry, walkErr error) error {
+			if walkErr != nil || d.IsDir() {
+				return nil
+			}
+			rel, _ := filepath.Rel(archRoot, path)
+			entry.Files["architecture"] = append(entry.Files["architecture"], filepath.ToSlash(rel))
+			if kindOf(d.Name()) != "" {
+				if content, err := os.ReadFile(path); err == nil {
+					text.Write(content)
+					text.WriteString("\n")
+				}
+			}
+			return nil
+		})
+		entry.text = text.String()
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// mentionedTech lists the decisions whose value appears as a word in the
+// promoted documents; weaker than matchTech, used only for scanned projects.
+func mentionedTech(ours map[string]string, text string) []string {
+	matched := []string{}
+	for decision, value := range ours {
+		pattern, err := regexp.Compile(`(?i)(^|[^a-z0-9])` + regexp.QuoteMeta(value) + `($|[^a-z0-9])`)
+		if err == nil && pattern.MatchString(text) {
+			matched = append(matched, decision)
+		}
+	}
+	sort.Strings(matched)
+	return matched
+}
+
+// domainRelative strips the upstream destination and version from a promoted
+// path (.../promotions/<slug>/<version>/adr/ADR-1.md -> adr/ADR-1.md); paths
+// from the pre-versioned layout keep their base name.
+func domainRelative(path, version string) string {
+	if version != "" {
+		if i := strings.Index(path, "/"+version+"/"); i >= 0 {
+			return path[i+len(version)+2:]
+		}
+	}
+	return filepath.Base(path)
+}
+
+func kindOf(name string) string {
+	for _, kind := range []string{"adr", "data-architecture", "compose-mode"} {
+		if importKinds[kind](name) {
+			return kind
+		}
+	}
+	return ""
+}
+
+// locate finds a promoted path in the upstream working trees; the index does
+// not record which repo a domain was published to.
+func locate(repos []string, path string) (string, string) {
+	if strings.Contains(path, "..") {
+		return "", ""
+	}
+	for _, repo := range repos {
+		candidate := filepath.Join(repo, filepath.FromSlash(path))
+		if isFile(candidate) {
+			return repo, candidate
+		}
+	}
+	return "", ""
+}
+
+// importFile writes the upstream document with its front matter removed (the
+// upstream copy stays the source of truth for doc_id and ownership) under a
+// provenance header, and marks it read-only.
+func importFile(src, dst, provenance string) (string, error) {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return "", err
+	}
+	content := "<!-- " + provenance + " -->\n" + stripFrontMatter(string(data))
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return "", err
+	}
+	if isFile(dst) {
+		_ = os.Chmod(dst, 0o644)
+	}
+	if err := os.WriteFile(dst, []byte(content), 0o644); err != nil {
+		return "", err
+	}
+	if err := os.Chmod(dst, 0o444); err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func stripFrontMatter(content string) string {
+	normalized := strings.ReplaceAll(content, "\r\n", "\n")
+	if !strings.HasPrefix(normalized, "---\n") {
+		return content
+	}
+	end := strings.Index(normalized[4:], "\n---")
+	if end < 0 {
+		return content
+	}
+	rest := normalized[4+end+len("\n---"):]
+	return strings.TrimLeft(rest, "\n")
+}
+
+func resolvePath(root, value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" || filepath.IsAbs(value) {
+		return value
+	}
+	return filepath.Join(root, filepath.FromSlash(value))
+}
+
+func resolveArgOrEnv(value, envKey string) string {
+	if strings.TrimSpace(value) != "" {
+		return strings.TrimSpace(value)
+	}
+	return strings.TrimSpace(os.Getenv(envKey))
+}
+
+func valueOr(value, fallback string) string {
+	if strings.TrimSpace(value) == "" {
+		return fallback
+	}
+	return strings.TrimSpace(value)
+}
+
+func normalizeSlug(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	b := strings.Builder{}
+	lastDash := false
+	for _, r := range value {
+		valid := (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9')
+		if valid {
+			b.WriteRune(r)
+			lastDash = false
+			continue
+		}
+		if !lastDash {
+			b.WriteRune('-')
+			lastDash = true
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return !info.IsDir()
+}
+
+func printBlocked(root string, issues []string) {
+	printReport(importReport{
+		Status:       "BLOCKED",
+		TargetRoot:   filepath.ToSlash(root),
+		Candidates:   []candidate{},
+		Imported:     []importedFile{},
+		Issues:       issues,
+		TimestampUTC: time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+func printReport(report importReport) {
+	data, _ := json.Marshal(report)
+	fmt.Println(string(data))
+}
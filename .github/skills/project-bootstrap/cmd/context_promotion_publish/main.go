@@ -1,12 +1,18 @@
 package main
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -22,6 +28,60 @@ type publishReport struct {
 	CopiedFiles      []string          `json:"copied_files"`
 	Issues           []string          `json:"issues"`
 	TimestampUTC     string            `json:"timestamp_utc"`
+	PublishMode      string            `json:"publish_mode"`
+	ManifestSource   string            `json:"manifest_source"`
+	RedactionPolicy  string            `json:"redaction_policy"`
+	Redactions       []redaction       `json:"redactions"`
+	Version          string            `json:"version"`
+	PrunedFiles      []string          `json:"pruned_files,omitempty"`
+	RemovedVersions  []string          `json:"removed_versions,omitempty"`
+	BundleManifest   string            `json:"bundle_manifest,omitempty"`
+	CatalogIndex     string            `json:"catalog_index,omitempty"`
+	Collisions       []string          `json:"collisions,omitempty"`
+	// BasenameCollisions lists files kept apart only by their directory, e.g.
+	// several phase-gate.md; they no longer overwrite each other.
+	BasenameCollisions []string    `json:"basename_collisions,omitempty"`
+	SourceCommit       string      `json:"source_commit,omitempty"`
+	ReportHash         string      `json:"report_hash,omitempty"`
+	GitCommits         []gitCommit `json:"git_commits,omitempty"`
+}
+
+// bundleFile is one promoted file: its source path in the target repo, its
+// bundle copy, and its path relative to the domain destination.
+type bundleFile struct {
+	source string
+	path   string
+	rel    string
+}
+
+// bundleManifestEntry describes one bundled file in bundle-manifest.json.
+type bundleManifestEntry struct {
+	Domain      string `json:"domain"`
+	Source      string `json:"source"`
+	Bundle      string `json:"bundle"`
+	Destination string `json:"destination"`
+	Size        int64  `json:"size"`
+	SHA256      string `json:"sha256"`
+}
+
+// redaction is one sensitive match found in a bundle file. The matched text
+// itself is never reported.
+type redaction struct {
+	File   string `json:"file"`
+	Bundle string `json:"bundle"`
+	Line   int    `json:"line"`
+	Rule   string `json:"rule"`
+	Action string `json:"action"`
+}
+
+// gitCommit records a promotion commit created in an upstream repo.
+type gitCommit struct {
+	Domains []string `json:"domains"`
+	Repo    string   `json:"repo"`
+	Branch  string   `json:"branch"`
+	Commit  string   `json:"commit"`
+	Parent  string   `json:"parent,omitempty"`
+	Files   int      `json:"files"`
 }
 
 func main() {
@@ -30,8 +90,21 @@ func main() {
 	catalogRepoRoot := flag.String("catalog-repo-root", "", "optional library catalog repo root")
 	projectSlug := flag.String("project-slug", "", "optional stable slug for promotion paths")
 	allowLocalBundle := flag.Bool("allow-local-bundle", false, "allow PASS without publishing to upstream repos")
+	manifestFile := flag.String("manifest", "promotion-manifest.yaml", "promotion manifest (domains, sources, destinations); a missing default file uses the built-in manifest")
+	snapshotVersion := flag.String("version", "", "snapshot version directory under each destination (defaults to the UTC timestamp)")
+	syncMode := flag.Bool("sync", false, "prune files in the published snapshot (and legacy flat files beside it) that are no longer in the bundle")
+	retain := flag.Int("retain", 0, "number of snapshot versions to keep per destination, including this one (0 keeps all)")
+	redactionFile := flag.String("redaction-policy", "docs/tooling/promotion-redaction-policy.yaml", "sensitive-content policy applied to bundle files; a missing default file uses the built-in rules")
+	openapiLint := flag.String("openapi-lint", "", "openapi_lint command source used to decode the manifest and redaction policy YAML (defaults to .github/skills/local-mcp-setup/cmd/openapi_lint/main.go under the working directory, then the target root)")
+	publishMode := flag.String("publish-mode", "copy", "copy: write into upstream working trees; git: commit the bundle on a promotion/<slug>/<timestamp> branch of each upstream repo (bare or working)")
 	flag.Parse()
 
+	mode := strings.ToLower(strings.TrimSpace(*publishMode))
+	if mode != "copy" && mode != "git" {
+		printBlocked("", "", []string{fmt.Sprintf("unsupported --publish-mode %q (use copy or git)", *publishMode)}, nil, nil)
+		return
+	}
+
 	root := strings.TrimSpace(*targetRoot)
 	if root == "" {
 		cwd, err := os.Getwd()
@@ -59,73 +132,142 @@ func main() {
 	catalogRoot := resolveArgOrEnv(strings.TrimSpace(*catalogRepoRoot), "CATALOG_REPO_ROOT")
 
 	bundleRoot := filepath.Join(absRoot, "docs", "tooling", "context-promotion-bundle")
+	// The bundle is rebuilt from scratch so files dropped from the project
+	// never linger in it.
+	_ = os.RemoveAll(bundleRoot)
 	_ = os.MkdirAll(bundleRoot, 0o755)
 
-	requiredSources := map[string][]string{
-		"architecture": {
-			"docs/data-architecture-decision.md",
-			"docs/integration/compose-mode-decision.md",
-		},
-		"catalog": {
-			"docs/plans/index.md",
-			"docs/handoffs/routing-matrix.md",
-		},
+	lintCommand := openapiLintCommand(absRoot, strings.TrimSpace(*openapiLint))
+	manifest, err := loadManifest(absRoot, strings.TrimSpace(*manifestFile), flagSet("manifest"), lintCommand)
+	if err != nil {
+		printBlocked(absRoot, slug, []string{fmt.Sprintf("invalid promotion manifest: %v", err)}, nil, nil)
+		return
 	}
-	optionalSources := map[string][]string{
-		"architecture": {
-			"docs/adr/ADR-0001-repo-naming-conventions.md",
-		},
-		"catalog": {
-			"docs/traceability-matrix.md",
-		},
+
+	redactions, err := loadRedactionPolicy(absRoot, strings.TrimSpace(*redactionFile), flagSet("redaction-policy"), lintCommand)
+	if err != nil {
+		printBlocked(absRoot, slug, []string{fmt.Sprintf("invalid redaction policy: %v", err)}, nil, nil)
+		return
 	}
 
+	now := time.Now().UTC()
+	version := strings.TrimSpace(*snapshotVersion)
+	if version == "" {
+		version = now.Format("20060102T150405Z")
+	}
+	if version == "latest" || strings.ContainsAny(version, "/\\") || strings.HasPrefix(version, ".") {
+		printBlocked(absRoot, slug, []string{fmt.Sprintf("invalid --version %q", version)}, nil, nil)
+		return
+	}
 	report := publishReport{
+		Version:          version,
+		PublishMode:      mode,
 		Status:           "PASS",
 		TargetRoot:       filepath.ToSlash(absRoot),
 		ProjectSlug:      slug,
 		BundleRoot:       filepath.ToSlash(bundleRoot),
+		ManifestSource:   manifest.Source,
+		RedactionPolicy:  redactions.Source,
+		Redactions:       []redaction{},
 		PublishedTargets: map[string]string{},
 		CopiedFiles:      []string{},
 		MissingRequired:  []string{},
 		MissingOptional:  []string{},
 		Issues:           []string{},
-		TimestampUTC:     time.Now().UTC().Format(time.RFC3339),
+		TimestampUTC:     now.Format(time.RFC3339),
 	}
+	report.SourceCommit = gitOutput(absRoot, nil, "rev-parse", "HEAD")
 
-	bundleFiles := map[string][]string{"architecture": {}, "catalog": {}}
-	for domain, rels := range requiredSources {
-		for _, rel := range rels {
-			src := filepath.Join(absRoot, filepath.FromSlash(rel))
-			if !isFile(src) {
-				report.MissingRequired = append(report.MissingRequired, rel)
+	bundleFiles := map[string][]bundleFile{}
+	claimed := map[string]string{}
+	basenames := map[string]map[string]bool{}
+	manifestEntries := []bundleManifestEntry{}
+	for _, domain := range manifest.Domains {
+		bundleFiles[domain.Name] = []bundleFile{}
+		destination := strings.ReplaceAll(domain.Destination, "{slug}", slug)
+		for _, source := range domain.Sources {
+			matches, matchErr := matchGlob(absRoot, source.Glob, bundleRoot)
+			if matchErr != nil {
+				report.Issues = append(report.Issues, fmt.Sprintf("invalid glob %s: %v", source.Glob, matchErr))
 				continue
 			}
-			dst := filepath.Join(bundleRoot, domain, filepath.Base(src))
-			if copyErr := copyFile(src, dst); copyErr != nil {
-				report.Issues = append(report.Issues, fmt.Sprintf("failed to copy %s: %v", rel, copyErr))
+			if len(matches) == 0 {
+				if source.Required {
+					report.MissingRequired = append(report.MissingRequired, source.Glob)
+				} else {
+					report.MissingOptional = append(report.MissingOptional, source.Glob)
+				}
 				continue
 			}
-			bundleFiles[domain] = append(bundleFiles[domain], dst)
-			report.CopiedFiles = append(report.CopiedFiles, filepath.ToSlash(dst))
+			prefix := globPrefix(source.Glob)
+			for _, rel := range matches {
+				src := filepath.Join(absRoot, filepath.FromSlash(rel))
+				inDomain := joinSlash(source.Subpath, relativeTo(prefix, rel))
+				key := domain.Name + "/" + inDomain
+				if owner, taken := claimed[key]; taken {
+					if owner != rel {
+						report.Collisions = append(report.Collisions, fmt.Sprintf("%s: %s and %s both map to %s", domain.Name, owner, rel, inDomain))
+					}
+					continue
+				}
+				claimed[key] = rel
+				if basenames[domain.Name] == nil {
+					basenames[domain.Name] = map[string]bool{}
+				}
+				basenames[domain.Name][inDomain] = true
+
+				dst := filepath.Join(bundleRoot, domain.Name, filepath.FromSlash(inDomain))
+				provenance := fmt.Sprintf("promoted from %s (project %s, source commit %s, %s)", rel, slug, valueOr(report.SourceCommit, "unknown"), report.TimestampUTC)
+				if copyErr := writeBundleFile(src, dst, source.Transform, provenance); copyErr != nil {
+					report.Issues = append(report.Issues, fmt.Sprintf("failed to copy %s: %v", rel, copyErr))
+					continue
+				}
+				found, scanErr := redactions.apply(dst)
+				if scanErr != nil {
+					report.Issues = append(report.Issues, fmt.Sprintf("failed to scan %s: %v", rel, scanErr))
+					continue
+				}
+				for _, item := range found {
+					item.File = rel
+					item.Bundle = domain.Name + "/" + inDomain
+					report.Redactions = append(report.Redactions, item)
+				}
+				bundleFiles[domain.Name] = append(bundleFiles[domain.Name], bundleFile{source: rel, path: dst, rel: inDomain})
+				report.CopiedFiles = append(report.CopiedFiles, filepath.ToSlash(dst))
+				entry, entryErr := newManifestEntry(absRoot, domain.Name, rel, dst, destination+"/"+version+"/"+inDomain)
+				if entryErr != nil {
+					report.Issues = append(report.Issues, fmt.Sprintf("failed to hash %s: %v", rel, entryErr))
+					continue
+				}
+				manifestEntries = append(manifestEntries, entry)
+			}
 		}
 	}
-	for domain, rels := range optionalSources {
-		for _, rel := range rels {
-			src := filepath.Join(absRoot, filepath.FromSlash(rel))
-			if !isFile(src) {
-				report.MissingOptional = append(report.MissingOptional, rel)
-				continue
-			}
-			dst := filepath.Join(bundleRoot, domain, filepath.Base(src))
-			if copyErr := copyFile(src, dst); copyErr != nil {
-				report.Issues = append(report.Issues, fmt.Sprintf("failed to copy optional %s: %v", rel, copyErr))
-				continue
-			}
-			bundleFiles[domain] = append(bundleFiles[domain], dst)
-			report.CopiedFiles = append(report.CopiedFiles, filepath.ToSlash(dst))
+	report.BasenameCollisions = basenameCollisions(basenames)
+	if len(report.Collisions) > 0 {
+		report.Status = "BLOCKED"
+		report.Issues = append(report.Issues, "promotion sources collide on the same bundle path")
+	}
+
+	bundleManifestPath := filepath.Join(bundleRoot, "bundle-manifest.json")
+	if data, marshalErr := json.MarshalIndent(manifestEntries, "", "  "); marshalErr == nil {
+		if writeErr := os.WriteFile(bundleManifestPath, append(data, '\n'), 0o644); writeErr != nil {
+			report.Issues = append(report.Issues, fmt.Sprintf("failed to write bundle manifest: %v", writeErr))
+		} else {
+			report.BundleManifest = filepath.ToSlash(bundleManifestPath)
+		}
+	}
+
+	blockedContent := 0
+	for _, item := range report.Redactions {
+		if item.Action == "block" {
+			blockedContent++
 		}
 	}
+	if blockedContent > 0 {
+		report.Status = "BLOCKED"
+		report.Issues = append(report.Issues, fmt.Sprintf("sensitive content blocks promotion: %d finding(s) with action block (see redactions)", blockedContent))
+	}
 
 	if len(report.MissingRequired) > 0 {
 		sort.Strings(report.MissingRequired)
@@ -133,37 +275,653 @@ func main() {
 		report.Issues = append(report.Issues, "required promotion sources missing")
 	}
 
-	publishedAny := false
-	if archRoot != "" {
-		absArch, _ := filepath.Abs(archRoot)
-		target := filepath.Join(absArch, "docs", "source", "02-architecture", "promotions", slug)
-		if publishFiles(bundleFiles["architecture"], target, &report) {
-			report.PublishedTargets["architecture"] = filepath.ToSlash(target)
-			publishedAny = true
+	upstreamRoots := map[string]string{"architecture": archRoot, "catalog": catalogRoot}
+	targets := []publishTarget{}
+	targetIndex := map[string]int{}
+	for _, domain := range manifest.Domains {
+		repoRoot := upstreamRoots[domain.Upstream]
+		if repoRoot == "" {
+			continue
+		}
+		absRepo, _ := filepath.Abs(repoRoot)
+		i, ok := targetIndex[absRepo]
+		if !ok {
+			i = len(targets)
+			targetIndex[absRepo] = i
+			targets = append(targets, publishTarget{repo: absRepo})
 		}
+		subdir := strings.ReplaceAll(domain.Destination, "{slug}", slug)
+		targets[i].domains = append(targets[i].domains, domainTarget{name: domain.Name, subdir: subdir, files: bundleFiles[domain.Name]})
 	}
 	if catalogRoot != "" {
 		absCatalog, _ := filepath.Abs(catalogRoot)
-		target := filepath.Join(absCatalog, "docs", "artifacts", "promotions", slug)
-		if publishFiles(bundleFiles["catalog"], target, &report) {
-			report.PublishedTargets["catalog"] = filepath.ToSlash(target)
+		if i, ok := targetIndex[absCatalog]; ok {
+			entry := newPromotionIndexEntry(absRoot, targets, redactions, report)
+			targets[i].index = &entry
+		}
+	}
+	policy := snapshotPolicy{version: version, sync: *syncMode, retain: *retain}
+	if policy.retain < 0 {
+		policy.retain = 0
+	}
+
+	if mode == "git" {
+		if report.SourceCommit == "" {
+			report.SourceCommit = "unknown"
+		}
+		report.ReportHash = reportHash(report)
+	}
+
+	// The report is written before any upstream write so every redaction is
+	// on record even if publishing fails part way.
+	reportPath := filepath.Join(absRoot, "docs", "tooling", "context-promotion-report.json")
+	writeReport(reportPath, report)
+
+	// Nothing is published once the bundle is BLOCKED (missing required
+	// sources, collisions, blocked content), in copy and git mode alike.
+	publishedAny := false
+	for _, target := range targets {
+		if report.Status == "BLOCKED" {
+			break
+		}
+		if mode == "git" {
+			branch := fmt.Sprintf("promotion/%s/%s", slug, now.Format("20060102T150405Z"))
+			message := fmt.Sprintf("Promote %s context for %s\n\nSource commit: %s\nReport hash: sha256:%s\n", strings.Join(target.domainNames(), ", "), slug, report.SourceCommit, report.ReportHash)
+			commit, err := publishGit(target, branch, message, policy, &report)
+			if err != nil {
+				report.Issues = append(report.Issues, fmt.Sprintf("git publish to %s failed: %v", filepath.ToSlash(target.repo), err))
+				continue
+			}
+			report.GitCommits = append(report.GitCommits, commit)
+			if target.index != nil {
+				report.CatalogIndex = filepath.ToSlash(target.repo) + "@" + branch + ":" + catalogIndexDir + "/index.json"
+			}
+			for _, domain := range target.domains {
+				report.PublishedTargets[domain.name] = filepath.ToSlash(target.repo) + "@" + branch
+			}
 			publishedAny = true
+			continue
+		}
+		targetPublished := false
+		for _, domain := range target.domains {
+			dir := filepath.Join(target.repo, filepath.FromSlash(domain.subdir))
+			if publishFiles(domain.files, filepath.Join(dir, version), &report) {
+				if err := os.WriteFile(filepath.Join(dir, "latest"), []byte(version+"\n"), 0o644); err != nil {
+					report.Issues = append(report.Issues, fmt.Sprintf("failed to update latest pointer in %s: %v", filepath.ToSlash(dir), err))
+				}
+				pruneSnapshotDir(dir, domain, policy, &report)
+				report.PublishedTargets[domain.name] = filepath.ToSlash(filepath.Join(dir, version))
+				targetPublished = true
+			}
+		}
+		publishedAny = publishedAny || targetPublished
+		if target.index != nil && targetPublished {
+			indexDir := filepath.Join(target.repo, filepath.FromSlash(catalogIndexDir))
+			if err := writePromotionIndex(indexDir, *target.index); err != nil {
+				report.Issues = append(report.Issues, fmt.Sprintf("failed to update promotion index: %v", err))
+			} else {
+				report.CatalogIndex = filepath.ToSlash(filepath.Join(indexDir, "index.json"))
+			}
 		}
 	}
 
 	if !publishedAny && !*allowLocalBundle {
 		report.Status = "BLOCKED"
-		report.Issues = append(report.Issues, "no upstream publish target configured; set ARCHITECTURE_REPO_ROOT and CATALOG_REPO_ROOT or use --allow-local-bundle")
+		if len(targets) == 0 {
+			report.Issues = append(report.Issues, "no upstream publish target configured; set ARCHITECTURE_REPO_ROOT and CATALOG_REPO_ROOT or use --allow-local-bundle")
+		} else {
+			report.Issues = append(report.Issues, "bundle was not published to any upstream target")
+		}
 	}
 
-	reportPath := filepath.Join(absRoot, "docs", "tooling", "context-promotion-report.json")
-	if err := os.MkdirAll(filepath.Dir(reportPath), 0o755); err == nil {
+	writeReport(reportPath, report)
+	printReport(report)
+}
+
+func writeReport(path string, report publishReport) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err == nil {
 		if data, err := json.MarshalIndent(report, "", "  "); err == nil {
-			_ = os.WriteFile(reportPath, data, 0o644)
+			_ = os.WriteFile(path, data, 0o644)
 		}
 	}
+}
 
-	printReport(report)
+type promotionManifest struct {
+	Source  string
+	Domains []promotionDomain
+}
+
+type promotionDomain struct {
+	Name        string
+	Upstream    string
+	Destination string
+	Sources     []promotionSource
+}
+
+type promotionSource struct {
+	Glob      string
+	Required  bool
+	Subpath   string
+	Transform string
+}
+
+var validTransforms = map[string]bool{"none": true, "strip-front-matter": true, "provenance-header": true}
+
+// defaultManifest is the promotion set used before manifests existed.
+func defaultManifest() promotionManifest {
+	return promotionManifest{
+		Source: "built-in",
+		Domains: []promotionDomain{
+			{
+				Name:        "architecture",
+				Upstream:    "architecture",
+				Destination: "docs/source/02-architecture/promotions/{slug}",
+				Sources: []promotionSource{
+					{Glob: "docs/data-architecture-decision.md", Required: true, Transform: "none"},
+					{Glob: "docs/integration/compose-mode-decision.md", Required: true, Transform: "none"},
+					{Glob: "docs/adr/ADR-0001-repo-naming-conventions.md", Transform: "none"},
+				},
+			},
+			{
+				Name:        "catalog",
+				Upstream:    "catalog",
+				Destination: "docs/artifacts/promotions/{slug}",
+				Sources: []promotionSource{
+					{Glob: "docs/plans/index.md", Required: true, Transform: "none"},
+					{Glob: "docs/handoffs/routing-matrix.md", Required: true, Transform: "none"},
+					{Glob: "docs/traceability-matrix.md", Transform: "none"},
+				},
+			},
+		},
+	}
+}
+
+// loadManifest reads promotion-manifest.yaml:
+//
+//	domains:
+//	  architecture:
+//	    upstream: architecture        # architecture | catalog repo root
+//	    destination: docs/source/02-architecture/promotions/{slug}
+//	    sources:
+//	      - glob: docs/adr/*.md
+//	        required: false
+//	        subpath: adr
+//	        transform: provenance-header   # none | strip-front-matter | provenance-header
+func loadManifest(root, value string, explicit bool, lintCommand string) (promotionManifest, error) {
+	if value == "" {
+		return defaultManifest(), nil
+	}
+	path := value
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, filepath.FromSlash(path))
+	}
+	if _, err := os.Stat(path); err != nil {
+		if explicit {
+			return promotionManifest{}, err
+		}
+		return defaultManifest(), nil
+	}
+	values, err := readYAMLPaths(path, lintCommand)
+	if err != nil {
+		return promotionManifest{}, fmt.Errorf("%s: %v", filepath.ToSlash(path), err)
+	}
+	seen := map[string]bool{}
+	names := []string{}
+	for key := range values {
+		if !strings.HasPrefix(key, "domains.") {
+			continue
+		}
+		name := strings.SplitN(strings.TrimPrefix(key, "domains."), ".", 2)[0]
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return promotionManifest{}, fmt.Errorf("%s: no domains defined", filepath.ToSlash(path))
+	}
+	sort.Strings(names)
+
+	manifest := promotionManifest{Source: filepath.ToSlash(path)}
+	for _, name := range names {
+		prefix := "domains." + name + "."
+		domain := promotionDomain{
+			Name:        name,
+			Upstream:    valueOr(strings.TrimSpace(values[prefix+"upstream"]), name),
+			Destination: strings.Trim(filepath.ToSlash(strings.TrimSpace(values[prefix+"destination"])), "/"),
+		}
+		if domain.Upstream != "architecture" && domain.Upstream != "catalog" {
+			return promotionManifest{}, fmt.Errorf("domain %s: upstream must be architecture or catalog", name)
+		}
+		if domain.Destination == "" || strings.Contains(domain.Destination, "..") {
+			return promotionManifest{}, fmt.Errorf("domain %s: destination must be a relative path inside the upstream repo", name)
+		}
+		for i := 0; i < yamlItems(values, prefix+"sources"); i++ {
+			item := prefix + "sources." + strconv.Itoa(i)
+			if _, scalar := values[item]; scalar {
+				return promotionManifest{}, fmt.Errorf("domain %s: source %d is not a mapping", name, i+1)
+			}
+			source := promotionSource{
+				Glob:      strings.TrimSpace(values[item+".glob"]),
+				Required:  values[item+".required"] == "true",
+				Subpath:   strings.Trim(filepath.ToSlash(strings.TrimSpace(values[item+".subpath"])), "/"),
+				Transform: valueOr(strings.TrimSpace(values[item+".transform"]), "none"),
+			}
+			if source.Glob == "" {
+				return promotionManifest{}, fmt.Errorf("domain %s: source %d has no glob", name, i+1)
+			}
+			if strings.Contains(source.Subpath, "..") {
+				return promotionManifest{}, fmt.Errorf("domain %s: subpath %q leaves the destination", name, source.Subpath)
+			}
+			if !validTransforms[source.Transform] {
+				return promotionManifest{}, fmt.Errorf("domain %s: unknown transform %q", name, source.Transform)
+			}
+			domain.Sources = append(domain.Sources, source)
+		}
+		manifest.Domains = append(manifest.Domains, domain)
+	}
+	return manifest, nil
+}
+
+// redactionRule matches one kind of sensitive content. When the pattern has a
+// named group "secret" only that group is replaced, so `password: hunter2`
+// keeps its key.
+type redactionRule struct {
+	ID      string
+	Pattern *regexp.Regexp
+	Action  string
+}
+
+type redactionPolicy struct {
+	Source string
+	Rules  []redactionRule
+	Allow  []*regexp.Regexp
+}
+
+var validRedactionActions = map[string]bool{"redact": true, "block": true, "off": true}
+
+// builtinRedactionRules cover credentials (blocked: a leaked key must be
+// rotated, not hidden) and internal identifiers (redacted).
+func builtinRedactionRules() []redactionRule {
+	rules := []struct{ id, pattern, action string }{
+		{"private-key", `-----BEGIN [A-Z ]*PRIVATE KEY-----`, "block"},
+		{"aws-access-key", `\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`, "block"},
+		{"github-token", `\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{40,})\b`, "block"},
+		{"slack-token", `\bxox[abprs]-[A-Za-z0-9-]{10,}\b`, "block"},
+		{"bearer-token", `(?i)\bbearer\s+(?P<secret>[A-Za-z0-9\-._~+/]{20,}=*)`, "block"},
+		{"connection-string-password", `\b[a-zA-Z][a-zA-Z0-9+.-]*://[^\s:/@]+:(?P<secret>[^\s@/]+)@`, "redact"},
+		{"credential-assignment", `(?i)\b(?:password|passwd|pwd|secret|api[_-]?key|access[_-]?key|access[_-]?token|auth[_-]?token|client[_-]?secret)\b["']?\s*[:=]\s*["']?(?P<secret>[^\s"'<>` + "`" + `,;]{4,})`, "redact"},
+		{"private-hostname", `(?i)\b[a-z0-9](?:[a-z0-9-]*[a-z0-9])?(?:\.[a-z0-9](?:[a-z0-9-]*[a-z0-9])?)*\.(?:internal|corp|intranet|lan|local)\b`, "redact"},
+		{"private-ip", `\b(?:10(?:\.\d{1,3}){3}|192\.168(?:\.\d{1,3}){2}|172\.(?:1[6-9]|2\d|3[01])(?:\.\d{1,3}){2})\b`, "redact"},
+		{"email", `\b[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}\b`, "redact"},
+	}
+	out := make([]redactionRule, 0, len(rules))
+	for _, rule := range rules {
+		out = append(out, redactionRule{ID: rule.id, Pattern: regexp.MustCompile(rule.pattern), Action: rule.action})
+	}
+	return out
+}
+
+// builtinRedactionAllow skips documentation placeholders and text an
+// earlier rule already redacted.
+var builtinRedactionAllow = []string{
+	`^\[REDACTED:`,
+	`(?i)[@.]example\.(?:com|org|net)$`,
+	`^\$\{[^}]*\}$`,
+	`^\{\{.*\}\}$`,
+	`(?i)^(?:changeme|redacted|placeholder|none|null|true|false|required|optional)$`,
+}
+
+// loadRedactionPolicy reads the promotion redaction policy:
+//
+//	builtin_rules: true          # keep the built-in rules (default)
+//	rules:
+//	  - id: customer-codename
+//	    pattern: '(?i)\bproject-falcon\b'
+//	    action: block            # redact | block | off
+//	  - id: email                # a built-in id without pattern changes its action
+//	    action: off
+//	allow:
+//	  - '@ourcompany-public\.com$'
+//
+// Allow patterns are tested against the matched text.
+func loadRedactionPolicy(root, value string, explicit bool, lintCommand string) (redactionPolicy, error) {
+	policy := redactionPolicy{Source: "built-in", Rules: builtinRedactionRules()}
+	for _, pattern := range builtinRedactionAllow {
+		policy.Allow = append(policy.Allow, regexp.MustCompile(pattern))
+	}
+	if value == "" {
+		return policy, nil
+	}
+	path := value
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, filepath.FromSlash(path))
+	}
+	if _, err := os.Stat(path); err != nil {
+		if explicit {
+			return redactionPolicy{}, err
+		}
+		return policy, nil
+	}
+	values, err := readYAMLPaths(path, lintCommand)
+	if err != nil {
+		return redactionPolicy{}, fmt.Errorf("%s: %v", filepath.ToSlash(path), err)
+	}
+	policy.Source = filepath.ToSlash(path)
+	if values["builtin_rules"] == "false" {
+		policy.Rules = nil
+	}
+	for i := 0; i < yamlItems(values, "rules"); i++ {
+		item := "rules." + strconv.Itoa(i)
+		if _, scalar := values[item]; scalar {
+			return redactionPolicy{}, fmt.Errorf("rule %d is not a mapping", i+1)
+		}
+		id := strings.TrimSpace(values[item+".id"])
+		action := strings.ToLower(valueOr(strings.TrimSpace(values[item+".action"]), "redact"))
+		pattern := strings.TrimSpace(values[item+".pattern"])
+		if id == "" {
+			return redactionPolicy{}, fmt.Errorf("rule %d has no id", i+1)
+		}
+		if !validRedactionActions[action] {
+			return redactionPolicy{}, fmt.Errorf("rule %s: unknown action %q", id, action)
+		}
+		existing := -1
+		for j, rule := range policy.Rules {
+			if rule.ID == id {
+				existing = j
+			}
+		}
+		rule := redactionRule{ID: id, Action: action}
+		switch {
+		case pattern != "":
+			compiled, compileErr := regexp.Compile(pattern)
+			if compileErr != nil {
+				return redactionPolicy{}, fmt.Errorf("rule %s: %v", id, compileErr)
+			}
+			rule.Pattern = compiled
+		case existing >= 0:
+			rule.Pattern = policy.Rules[existing].Pattern
+		default:
+			return redactionPolicy{}, fmt.Errorf("rule %s has no pattern", id)
+		}
+		if existing >= 0 {
+			policy.Rules[existing] = rule
+		} else {
+			policy.Rules = append(policy.Rules, rule)
+		}
+	}
+	for _, pattern := range yamlList(values, "allow") {
+		compiled, compileErr := regexp.Compile(pattern)
+		if compileErr != nil {
+			return redactionPolicy{}, fmt.Errorf("allow %q: %v", pattern, compileErr)
+		}
+		policy.Allow = append(policy.Allow, compiled)
+	}
+	return policy, nil
+}
+
+// apply scans a bundle file in place; binary files are skipped.
+func (p redactionPolicy) apply(path string) ([]redaction, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.IndexByte(data, 0) >= 0 {
+		return nil, nil
+	}
+	text, found := p.scan(string(data))
+	if text != string(data) {
+		if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+			return nil, err
+		}
+	}
+	return found, nil
+}
+
+// scan checks text line by line, rewrites redact matches to
+// [REDACTED:<rule>] and returns every finding. Block matches are left in
+// place because they stop the publish.
+func (p redactionPolicy) scan(text string) (string, []redaction) {
+	found := []redaction{}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		for _, rule := range p.Rules {
+			if rule.Action == "off" {
+				continue
+			}
+			secretGroup := rule.Pattern.SubexpIndex("secret")
+			var b strings.Builder
+			last := 0
+			for _, match := range rule.Pattern.FindAllStringSubmatchIndex(line, -1) {
+				start, end := match[0], match[1]
+				if secretGroup > 0 && match[2*secretGroup] >= 0 {
+					start, end = match[2*secretGroup], match[2*secretGroup+1]
+				}
+				if start == end || p.allowed(line[start:end]) {
+					continue
+				}
+				found = append(found, redaction{Line: i + 1, Rule: rule.ID, Action: rule.Action})
+				if rule.Action != "redact" {
+					continue
+				}
+				b.WriteString(line[last:start])
+				b.WriteString("[REDACTED:" + rule.ID + "]")
+				last = end
+			}
+			if last > 0 {
+				b.WriteString(line[last:])
+				line = b.String()
+				lines[i] = line
+			}
+		}
+	}
+	return strings.Join(lines, "\n"), found
+}
+
+func (p redactionPolicy) allowed(text string) bool {
+	for _, pattern := range p.Allow {
+		if pattern.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob returns target-relative files matching a slash glob, walking only
+// below the glob's static prefix and skipping .git and the bundle itself.
+func matchGlob(root, glob, bundleRoot string) ([]string, error) {
+	glob = strings.TrimPrefix(filepath.ToSlash(strings.TrimSpace(glob)), "./")
+	pattern, err := regexp.Compile(globToRegex(glob))
+	if err != nil {
+		return nil, err
+	}
+	base := filepath.Join(root, filepath.FromSlash(globPrefix(glob)))
+	matches := []string{}
+	_ = filepath.WalkDir(base, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" || path == bundleRoot {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, relErr := filepath.Rel(root, path)
+		if relErr == nil && pattern.MatchString(filepath.ToSlash(rel)) {
+			matches = append(matches, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// globPrefix is the directory part of a glob before its first wildcard.
+func globPrefix(glob string) string {
+	cut := strings.IndexAny(glob, "*?[")
+	if cut < 0 {
+		return filepath.ToSlash(filepath.Dir(glob))
+	}
+	prefix := glob[:cut]
+	if slash := strings.LastIndex(prefix, "/"); slash >= 0 {
+		return prefix[:slash]
+	}
+	return "."
+}
+
+// globToRegex converts a slash-separated glob to an anchored regex: `**`
+// matches any number of path segments, `*` and `?` stay within one segment.
+func globToRegex(glob string) string {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+				if i+1 < len(glob) && glob[i+1] == '/' {
+					i++
+					b.WriteString("(?:.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+				continue
+			}
+			b.WriteString("[^/]*")
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
+
+// writeBundleFile copies src into the bundle applying the source transform.
+// Provenance headers use the comment syntax of the file type; formats
+// without comments (JSON) are copied unchanged.
+func writeBundleFile(src, dst, transform, provenance string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	content := string(data)
+	ext := strings.ToLower(filepath.Ext(src))
+	switch transform {
+	case "strip-front-matter":
+		content = stripFrontMatter(content)
+	case "provenance-header":
+		switch ext {
+		case ".md", ".markdown":
+			content = "<!-- " + provenance + " -->\n" + content
+		case ".yaml", ".yml", ".mmd":
+			prefix := "# "
+			if ext == ".mmd" {
+				prefix = "%% "
+			}
+			content = prefix + provenance + "\n" + content
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(dst, []byte(content), 0o644)
+}
+
+func stripFrontMatter(content string) string {
+	normalized := strings.ReplaceAll(content, "\r\n", "\n")
+	if !strings.HasPrefix(normalized, "---\n") {
+		return content
+	}
+	end := strings.Index(normalized[4:], "\n---")
+	if end < 0 {
+		return content
+	}
+	rest := normalized[4+end+len("\n---"):]
+	return strings.TrimLeft(rest, "\n")
+}
+
+func newManifestEntry(root, domain, source, bundlePath, destination string) (bundleManifestEntry, error) {
+	data, err := os.ReadFile(bundlePath)
+	if err != nil {
+		return bundleManifestEntry{}, err
+	}
+	sum := sha256.Sum256(data)
+	bundleRel, relErr := filepath.Rel(root, bundlePath)
+	if relErr != nil {
+		bundleRel = bundlePath
+	}
+	return bundleManifestEntry{
+		Domain:      domain,
+		Source:      source,
+		Bundle:      filepath.ToSlash(bundleRel),
+		Destination: destination,
+		Size:        int64(len(data)),
+		SHA256:      hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+// relativeTo strips a glob's static prefix so matches keep the directory
+// structure below it; literal paths reduce to their base name.
+func relativeTo(prefix, rel string) string {
+	if prefix == "." || prefix == "" {
+		return rel
+	}
+	return strings.TrimPrefix(rel, prefix+"/")
+}
+
+func basenameCollisions(paths map[string]map[string]bool) []string {
+	out := []string{}
+	domains := make([]string, 0, len(paths))
+	for domain := range paths {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+	for _, domain := range domains {
+		byBase := map[string][]string{}
+		for path := range paths[domain] {
+			byBase[filepath.Base(path)] = append(byBase[filepath.Base(path)], path)
+		}
+		bases := make([]string, 0, len(byBase))
+		for base := range byBase {
+			bases = append(bases, base)
+		}
+		sort.Strings(bases)
+		for _, base := range bases {
+			if len(byBase[base]) < 2 {
+				continue
+			}
+			sort.Strings(byBase[base])
+			out = append(out, fmt.Sprintf("%s: %s (%s)", domain, base, strings.Join(byBase[base], ", ")))
+		}
+	}
+	return out
+}
+
+func joinSlash(dir, name string) string {
+	if dir == "" {
+		return name
+	}
+	return dir + "/" + name
+}
+
+func valueOr(value, fallback string) string {
+	if strings.TrimSpace(value) == "" {
+		return fallback
+	}
+	return strings.TrimSpace(value)
+}
+
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
 }
 
 func resolveArgOrEnv(value, envKey string) string {
@@ -211,7 +969,7 @@ func copyFile(src, dst string) error {
 	return os.WriteFile(dst, data, 0o644)
 }
 
-func publishFiles(files []string, target string, report *publishReport) bool {
+func publishFiles(files []bundleFile, target string, report *publishReport) bool {
 	if len(files) == 0 {
 		report.Issues = append(report.Issues, fmt.Sprintf("no files available for publish target %s", filepath.ToSlash(target)))
 		return false
@@ -221,9 +979,9 @@ func publishFiles(files []string, target string, report *publishReport) bool {
 		return false
 	}
 	for _, file := range files {
-		dst := filepath.Join(target, filepath.Base(file))
-		if err := copyFile(file, dst); err != nil {
-			report.Issues = append(report.Issues, fmt.Sprintf("failed to publish %s: %v", filepath.ToSlash(file), err))
+		dst := filepath.Join(target, filepath.FromSlash(file.rel))
+		if err := copyFile(file.path, dst); err != nil {
+			report.Issues = append(report.Issues, fmt.Sprintf("failed to publish %s: %v", filepath.ToSlash(file.path), err))
 			continue
 		}
 		report.CopiedFiles = append(report.CopiedFiles, filepath.ToSlash(dst))
@@ -231,6 +989,541 @@ func publishFiles(files []string, target string, report *publishReport) bool {
 	return true
 }
 
+// publishTarget groups the domains promoted into one upstream repo so git
+// mode makes a single commit per repo.
+type publishTarget struct {
+	repo    string
+	domains []domainTarget
+	// index is set on the catalog repo target, which also maintains the
+	// promotion index.
+	index *promotionIndexEntry
+}
+
+type domainTarget struct {
+	name   string
+	subdir string
+	files  []bundleFile
+}
+
+func (d domainTarget) keep() map[string]bool {
+	keep := map[string]bool{}
+	for _, file := range d.files {
+		keep[file.rel] = true
+	}
+	return keep
+}
+
+// snapshotPolicy controls versioned publishing: files go to
+// <destination>/<version>/, a `latest` file names the newest version, sync
+// prunes stale files and retain bounds how many versions are kept.
+type snapshotPolicy struct {
+	version string
+	sync    bool
+	retain  int
+}
+
+// prune decides which destination-relative paths to delete. With sync, files
+// in this version's directory that are not in the bundle go, as do flat files
+// left by the pre-versioned layout. With retain, the oldest versions beyond
+// the limit go entirely.
+func (p snapshotPolicy) prune(existing []string, keep map[string]bool) ([]string, []string) {
+	remove := []string{}
+	versionFiles := map[string][]string{}
+	for _, rel := range existing {
+		first, rest, nested := strings.Cut(rel, "/")
+		if !nested {
+			if p.sync && rel != "latest" {
+				remove = append(remove, rel)
+			}
+			continue
+		}
+		versionFiles[first] = append(versionFiles[first], rel)
+		if p.sync && first == p.version && !keep[rest] {
+			remove = append(remove, rel)
+		}
+	}
+
+	removedVersions := []string{}
+	if p.retain > 0 {
+		versions := []string{}
+		for version := range versionFiles {
+			if version != p.version {
+				versions = append(versions, version)
+			}
+		}
+		sort.Slice(versions, func(i, j int) bool { return naturalLess(versions[i], versions[j]) })
+		excess := len(versions) + 1 - p.retain
+		for i := 0; i < excess && i < len(versions); i++ {
+			removedVersions = append(removedVersions, versions[i])
+			remove = append(remove, versionFiles[versions[i]]...)
+		}
+	}
+	sort.Strings(remove)
+	return uniqueSorted(remove), removedVersions
+}
+
+// pruneSnapshotDir applies the snapshot policy to a working-tree destination.
+func pruneSnapshotDir(dir string, domain domainTarget, policy snapshotPolicy, report *publishReport) {
+	existing := []string{}
+	_ = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if rel, relErr := filepath.Rel(dir, path); relErr == nil {
+			existing = append(existing, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	remove, removedVersions := policy.prune(existing, domain.keep())
+	for _, rel := range remove {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.Remove(path); err != nil {
+			report.Issues = append(report.Issues, fmt.Sprintf("failed to prune %s: %v", filepath.ToSlash(path), err))
+			continue
+		}
+		report.PrunedFiles = append(report.PrunedFiles, filepath.ToSlash(path))
+		removeEmptyParents(filepath.Dir(path), dir)
+	}
+	for _, removed := range removedVersions {
+		_ = os.RemoveAll(filepath.Join(dir, removed))
+		report.RemovedVersions = append(report.RemovedVersions, domain.name+":"+removed)
+	}
+}
+
+func removeEmptyParents(dir, stop string) {
+	for dir != stop && strings.HasPrefix(dir, stop) {
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// naturalLess orders versions with numeric runs compared by value, so v1.10
+// sorts after v1.9 and timestamps sort chronologically.
+func naturalLess(a, b string) bool {
+	for a != "" && b != "" {
+		ai, bi := numericPrefix(a), numericPrefix(b)
+		if ai > 0 && bi > 0 {
+			na, nb := strings.TrimLeft(a[:ai], "0"), strings.TrimLeft(b[:bi], "0")
+			if len(na) != len(nb) {
+				return len(na) < len(nb)
+			}
+			if na != nb {
+				return na < nb
+			}
+			a, b = a[ai:], b[bi:]
+			continue
+		}
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) < len(b)
+}
+
+func numericPrefix(value string) int {
+	i := 0
+	for i < len(value) && value[i] >= '0' && value[i] <= '9' {
+		i++
+	}
+	return i
+}
+
+func uniqueSorted(values []string) []string {
+	out := []string{}
+	for i, value := range values {
+		if i == 0 || value != values[i-1] {
+			out = append(out, value)
+		}
+	}
+	return out
+}
+
+func (t publishTarget) domainNames() []string {
+	names := []string{}
+	for _, domain := range t.domains {
+		names = append(names, domain.name)
+	}
+	return names
+}
+
+// reportHash fingerprints the bundle contents recorded in the report so the
+// promotion commit can be tied back to the exact report that produced it.
+func reportHash(report publishReport) string {
+	files := append([]string{}, report.CopiedFiles...)
+	sort.Strings(files)
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n%s\n", report.ProjectSlug, report.SourceCommit, report.TimestampUTC)
+	for _, file := range files {
+		data, err := os.ReadFile(filepath.FromSlash(file))
+		if err != nil {
+			continue
+		}
+		sum := sha256.Sum256(data)
+		fmt.Fprintf(h, "%s %s\n", hex.EncodeToString(sum[:]), file)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// publishGit commits files into target.subdir on a new branch using git
+// plumbing and a temporary index, so it works for bare and working repos
+// without touching their checkout or needing a remote.
+func publishGit(target publishTarget, branch, message string, policy snapshotPolicy, report *publishReport) (gitCommit, error) {
+	commit := gitCommit{Domains: target.domainNames(), Repo: filepath.ToSlash(target.repo), Branch: branch}
+	staged := map[string]string{}
+	for _, domain := range target.domains {
+		for _, file := range domain.files {
+			staged[domain.subdir+"/"+policy.version+"/"+file.rel] = file.path
+		}
+	}
+	if len(staged) == 0 {
+		return commit, fmt.Errorf("no files available for %s", strings.Join(commit.Domains, ", "))
+	}
+	if gitOutput(target.repo, nil, "rev-parse", "--git-dir") == "" {
+		return commit, fmt.Errorf("%s is not a git repository", filepath.ToSlash(target.repo))
+	}
+	if gitOutput(target.repo, nil, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch) != "" {
+		return commit, fmt.Errorf("branch %s already exists", branch)
+	}
+
+	indexFile, err := os.CreateTemp("", "promotion-index-*")
+	if err != nil {
+		return commit, err
+	}
+	indexPath := indexFile.Name()
+	indexFile.Close()
+	os.Remove(indexPath)
+	defer os.Remove(indexPath)
+	env := append(gitIdentityEnv(target.repo), "GIT_INDEX_FILE="+indexPath)
+
+	commit.Parent = gitOutput(target.repo, nil, "rev-parse", "--verify", "--quiet", "HEAD^{commit}")
+	readTree := []string{"read-tree", "--empty"}
+	if commit.Parent != "" {
+		readTree = []string{"read-tree", commit.Parent}
+	}
+	if _, err := runGit(target.repo, env, readTree...); err != nil {
+		return commit, err
+	}
+	for _, domain := range target.domains {
+		listed, err := runGit(target.repo, env, "ls-files", "--", domain.subdir+"/")
+		if err != nil {
+			return commit, err
+		}
+		existing := []string{}
+		for _, line := range strings.Split(listed, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				existing = append(existing, strings.TrimPrefix(line, domain.subdir+"/"))
+			}
+		}
+		remove, removedVersions := policy.prune(existing, domain.keep())
+		for _, rel := range remove {
+			// A zero-mode --index-info entry drops the path; unlike
+			// --force-remove it also works in bare repos.
+			entry := "0 0000000000000000000000000000000000000000\t" + domain.subdir + "/" + rel + "\n"
+			if _, err := runGitInput(target.repo, env, entry, "update-index", "--index-info"); err != nil {
+				return commit, err
+			}
+			report.PrunedFiles = append(report.PrunedFiles, filepath.ToSlash(target.repo)+":"+domain.subdir+"/"+rel)
+		}
+		for _, removed := range removedVersions {
+			report.RemovedVersions = append(report.RemovedVersions, domain.name+":"+removed)
+		}
+		pointer, err := runGitInput(target.repo, env, policy.version+"\n", "hash-object", "-w", "--stdin")
+		if err != nil {
+			return commit, err
+		}
+		if _, err := runGit(target.repo, env, "update-index", "--add", "--cacheinfo", "100644,"+pointer+","+domain.subdir+"/latest"); err != nil {
+			return commit, err
+		}
+	}
+	paths := make([]string, 0, len(staged))
+	for path := range staged {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		blob, err := runGit(target.repo, env, "hash-object", "-w", "--", staged[path])
+		if err != nil {
+			return commit, err
+		}
+		if _, err := runGit(target.repo, env, "update-index", "--add", "--cacheinfo", "100644,"+blob+","+path); err != nil {
+			return commit, err
+		}
+	}
+	if target.index != nil {
+		existing := ""
+		if commit.Parent != "" {
+			existing = gitOutput(target.repo, nil, "show", commit.Parent+":"+catalogIndexDir+"/index.json")
+		}
+		index, err := upsertPromotionIndex([]byte(existing), *target.index)
+		if err != nil {
+			return commit, err
+		}
+		data, _ := json.MarshalIndent(index, "", "  ")
+		rendered := map[string]string{"index.json": string(data) + "\n", "index.md": renderPromotionIndex(index)}
+		for _, name := range []string{"index.json", "index.md"} {
+			blob, err := runGitInput(target.repo, env, rendered[name], "hash-object", "-w", "--stdin")
+			if err != nil {
+				return commit, err
+			}
+			if _, err := runGit(target.repo, env, "update-index", "--add", "--cacheinfo", "100644,"+blob+","+catalogIndexDir+"/"+name); err != nil {
+				return commit, err
+			}
+		}
+	}
+	tree, err := runGit(target.repo, env, "write-tree")
+	if err != nil {
+		return commit, err
+	}
+	args := []string{"commit-tree", tree, "-m", message}
+	if commit.Parent != "" {
+		args = append(args, "-p", commit.Parent)
+	}
+	commit.Commit, err = runGit(target.repo, env, args...)
+	if err != nil {
+		return commit, err
+	}
+	if _, err := runGit(target.repo, env, "update-ref", "refs/heads/"+branch, commit.Commit, ""); err != nil {
+		return commit, err
+	}
+	commit.Files = len(staged)
+	return commit, nil
+}
+
+// catalogIndexDir holds the catalog-wide promotion index, beside the
+// per-project promotion folders.
+const catalogIndexDir = "docs/artifacts/promotions"
+
+// promotionIndex is the catalog's record of the latest promotion per project.
+type promotionIndex struct {
+	UpdatedUTC string                `json:"updated_utc"`
+	Promotions []promotionIndexEntry `json:"promotions"`
+}
+
+type promotionIndexEntry struct {
+	Slug             string              `json:"slug"`
+	ProjectRoot      string              `json:"project_root"`
+	TimestampUTC     string              `json:"timestamp_utc"`
+	Version          string              `json:"version"`
+	SourceCommit     string              `json:"source_commit,omitempty"`
+	Files            map[string][]string `json:"files"`
+	DataArchitecture map[string]string   `json:"data_architecture"`
+	TechChoices      map[string]string   `json:"tech_choices"`
+}
+
+var decisionBullet = regexp.MustCompile(`^\s*[-*]\s+([^:]+):\s*(.*)$`)
+
+// newPromotionIndexEntry summarises this publish: upstream paths per domain,
+// the filled-in data-architecture decision bullets (passed through the
+// redaction policy) and the spec-tech-detect choices.
+func newPromotionIndexEntry(root string, targets []publishTarget, policy redactionPolicy, report publishReport) promotionIndexEntry {
+	entry := promotionIndexEntry{
+		Slug:             report.ProjectSlug,
+		ProjectRoot:      report.TargetRoot,
+		TimestampUTC:     report.TimestampUTC,
+		Version:          report.Version,
+		SourceCommit:     report.SourceCommit,
+		Files:            map[string][]string{},
+		DataArchitecture: map[string]string{},
+		TechChoices:      map[string]string{},
+	}
+	for _, target := range targets {
+		for _, domain := range target.domains {
+			for _, file := range domain.files {
+				entry.Files[domain.name] = append(entry.Files[domain.name], domain.subdir+"/"+report.Version+"/"+file.rel)
+			}
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(root, "docs", "data-architecture-decision.md")); err == nil {
+		text, _ := policy.scan(string(data))
+		for _, line := range strings.Split(text, "\n") {
+			match := decisionBullet.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+			value := strings.TrimSpace(match[2])
+			if value == "" || strings.HasPrefix(value, "<") {
+				continue
+			}
+			entry.DataArchitecture[strings.TrimSpace(match[1])] = value
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(root, "docs", "tooling", "spec-tech-detect.json")); err == nil {
+		var detect struct {
+			Detected map[string]struct {
+				Value string `json:"value"`
+			} `json:"detected"`
+		}
+		if json.Unmarshal(data, &detect) == nil {
+			for decision, candidate := range detect.Detected {
+				if value := strings.TrimSpace(candidate.Value); value != "" {
+					entry.TechChoices[decision] = value
+				}
+			}
+		}
+	}
+	return entry
+}
+
+// upsertPromotionIndex replaces the entry for the slug (one per project) and
+// keeps entries ordered by slug.
+func upsertPromotionIndex(existing []byte, entry promotionIndexEntry) (promotionIndex, error) {
+	index := promotionIndex{}
+	if len(bytes.TrimSpace(existing)) > 0 {
+		if err := json.Unmarshal(existing, &index); err != nil {
+			return index, fmt.Errorf("existing promotion index is not valid JSON: %v", err)
+		}
+	}
+	kept := []promotionIndexEntry{entry}
+	for _, item := range index.Promotions {
+		if item.Slug != entry.Slug {
+			kept = append(kept, item)
+		}
+	}
+	sort.Slice(kept, func(i, j int) bool { return kept[i].Slug < kept[j].Slug })
+	index.Promotions = kept
+	index.UpdatedUTC = entry.TimestampUTC
+	return index, nil
+}
+
+func renderPromotionIndex(index promotionIndex) string {
+	var b strings.Builder
+	b.WriteString("# Promotion Index\n\n")
+	b.WriteString("Generated by `context_promotion_publish` from `index.json`; do not edit by hand.\n\n")
+	b.WriteString("Updated (UTC): " + index.UpdatedUTC + "\n\n")
+	b.WriteString("| Slug | Version | Published (UTC) | Tech choices | Data architecture | Files |\n|---|---|---|---|---|---|\n")
+	for _, entry := range index.Promotions {
+		files := 0
+		for _, paths := range entry.Files {
+			files += len(paths)
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %d |\n", entry.Slug, entry.Version, entry.TimestampUTC, joinPairs(entry.TechChoices, "="), joinPairs(entry.DataArchitecture, ": "), files)
+	}
+	for _, entry := range index.Promotions {
+		fmt.Fprintf(&b, "\n## %s\n\n- Project root: %s\n- Source commit: %s\n", entry.Slug, entry.ProjectRoot, valueOr(entry.SourceCommit, "unknown"))
+		domains := make([]string, 0, len(entry.Files))
+		for domain := range entry.Files {
+			domains = append(domains, domain)
+		}
+		sort.Strings(domains)
+		for _, domain := range domains {
+			fmt.Fprintf(&b, "- %s:\n", domain)
+			for _, path := range entry.Files[domain] {
+				fmt.Fprintf(&b, "  - `%s`\n", path)
+			}
+		}
+	}
+	return b.String()
+}
+
+func joinPairs(values map[string]string, separator string) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, strings.ReplaceAll(key+separator+values[key], "|", "\\|"))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// writePromotionIndex upserts index.json and re-renders index.md, each via a
+// temp file and rename so readers never see a partial index.
+func writePromotionIndex(dir string, entry promotionIndexEntry) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	existing, err := os.ReadFile(filepath.Join(dir, "index.json"))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	index, err := upsertPromotionIndex(existing, entry)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(filepath.Join(dir, "index.json"), append(data, '\n')); err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(dir, "index.md"), []byte(renderPromotionIndex(index)))
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// gitIdentityEnv supplies a committer identity only when the repo has none,
+// so promotion commits never fail on unconfigured machines.
+func gitIdentityEnv(repo string) []string {
+	env := os.Environ()
+	if gitOutput(repo, nil, "config", "user.name") == "" && os.Getenv("GIT_AUTHOR_NAME") == "" {
+		env = append(env, "GIT_AUTHOR_NAME=context-promotion", "GIT_COMMITTER_NAME=context-promotion")
+	}
+	if gitOutput(repo, nil, "config", "user.email") == "" && os.Getenv("GIT_AUTHOR_EMAIL") == "" {
+		env = append(env, "GIT_AUTHOR_EMAIL=context-promotion@localhost", "GIT_COMMITTER_EMAIL=context-promotion@localhost")
+	}
+	return env
+}
+
+func runGit(repo string, env []string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+	if env != nil {
+		cmd.Env = env
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %v: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func runGitInput(repo string, env []string, input string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+	if env != nil {
+		cmd.Env = env
+	}
+	cmd.Stdin = strings.NewReader(input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %v: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func gitOutput(repo string, env []string, args ...string) string {
+	out, err := runGit(repo, env, args...)
+	if err != nil {
+		return ""
+	}
+	return out
+}
+
 func printBlocked(root, slug string, issues []string, missingRequired []string, missingOptional []string) {
 	report := publishReport{
 		Status:           "BLOCKED",
@@ -250,3 +1543,118 @@ func printReport(report publishReport) {
 	data, _ := json.Marshal(report)
 	fmt.Println(string(data))
 }
+
+// openapiLintCommand locates the openapi_lint source. Its --decode mode is the
+// repo's YAML reader, so the promotion
+// manifest and redaction policy get full YAML without a second parser.
+func openapiLintCommand(root, explicit string) string {
+	if explicit != "" {
+		if filepath.IsAbs(explicit) {
+			return explicit
+		}
+		return filepath.Join(root, filepath.FromSlash(explicit))
+	}
+	rel := filepath.Join(".github", "skills", "local-mcp-setup", "cmd", "openapi_lint", "main.go")
+	if cwd, err := os.Getwd(); err == nil && exists(filepath.Join(cwd, rel)) {
+		return filepath.Join(cwd, rel)
+	}
+	return filepath.Join(root, rel)
+}
+
+// readYAMLPaths decodes a YAML file with openapi_lint --decode and flattens the
+// document into dotted paths. Sequence items are numbered from 0, so domains.adr.sources.0.glob
+// is the first source glob of the adr domain.
+func readYAMLPaths(path, command string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if !exists(command) {
+		return nil, fmt.Errorf("openapi_lint command not found at %s (set --openapi-lint)", filepath.ToSlash(command))
+	}
+	cmd := exec.Command("go", "run", command, "--decode", filepath.Base(path))
+	cmd.Stdin = bytes.NewReader(data)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	var result struct {
+		Status   string   `json:"status"`
+		Issues   []string `json:"issues"`
+		Document any      `json:"document"`
+	}
+	if jsonErr := json.Unmarshal(output, &result); jsonErr != nil {
+		if err == nil {
+			err = jsonErr
+		}
+		return nil, fmt.Errorf("openapi_lint --decode: %v %s", err, strings.TrimSpace(stderr.String()))
+	}
+	if result.Status != "PASS" {
+		return nil, fmt.Errorf("%s", strings.Join(result.Issues, "; "))
+	}
+	values := map[string]string{}
+	flattenDocument(values, "", result.Document)
+	return values, nil
+}
+
+func exists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+func flattenDocument(values map[string]string, path string, node any) {
+	child := func(key string) string {
+		if path == "" {
+			return key
+		}
+		return path + "." + key
+	}
+	switch typed := node.(type) {
+	case map[string]any:
+		for key, value := range typed {
+			flattenDocument(values, child(key), value)
+		}
+	case []any:
+		for i, value := range typed {
+			flattenDocument(values, child(strconv.Itoa(i)), value)
+		}
+	case string:
+		values[path] = typed
+	case bool:
+		values[path] = strconv.FormatBool(typed)
+	case float64:
+		values[path] = strconv.FormatFloat(typed, 'f', -1, 64)
+	}
+}
+
+// yamlList returns the scalars of a sequence at key, or the value itself when
+// key holds a single scalar.
+func yamlList(values map[string]string, key string) []string {
+	out := []string{}
+	if value := strings.TrimSpace(values[key]); value != "" {
+		return append(out, value)
+	}
+	for i := 0; ; i++ {
+		value, ok := values[key+"."+strconv.Itoa(i)]
+		if !ok {
+			return out
+		}
+		if value = strings.TrimSpace(value); value != "" {
+			out = append(out, value)
+		}
+	}
+}
+
+// yamlItems counts the mapping items of a block sequence at key.
+func yamlItems(values map[string]string, key string) int {
+	count := 0
+	for path := range values {
+		if !strings.HasPrefix(path, key+".") {
+			continue
+		}
+		index := strings.SplitN(strings.TrimPrefix(path, key+"."), ".", 2)[0]
+		if n, err := strconv.Atoi(index); err == nil && n+1 > count {
+			count = n + 1
+		}
+	}
+	return count
+}